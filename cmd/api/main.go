@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -17,7 +18,7 @@ import (
 func main() {
 	cfg := config.LoadConfig()
 
-	database, err := db.NewPostgresConnection(cfg)
+	database, err := db.NewPostgresConnection(cfg.DB)
 	if err != nil {
 		log.Fatalf("Could not connect to database: %v", err)
 	}
@@ -30,21 +31,55 @@ func main() {
 		log.Fatalf("Migration error: %v", err)
 	}
 
-	swiftRepo := repository.NewSwiftRepository(database)
+	swiftRepo := repository.NewSwiftRepository(database, cfg.DB.QueryTimeout)
 	swiftService := service.NewSwiftService(swiftRepo)
 	swiftHandler := handler.NewSwiftHandler(swiftService)
+	adminHandler := handler.NewAdminHandler(swiftService, cfg.Import.Reader, cfg.Import.Options, importer.NewJobManager(swiftService))
 
-	// Wywołanie importu z pliku XLSX:
-	importFilePath := "swift_data.xlsx" // ścieżka do Twojego pliku
-	if err := importer.ImportSwiftCodesFromXLSX(importFilePath, swiftService); err != nil {
-		log.Printf("IMPORT ERROR: %v", err)
+	// Import przy starcie tylko na wyraźne żądanie (IMPORT_ON_STARTUP=true);
+	// na co dzień import uruchamia się przez swiftctl.
+	if cfg.Import.OnStartup {
+		importOnStartup(cfg.Import, swiftService)
 	}
 
 	router := chi.NewRouter()
-	router.Get("/v1/swift-codes/{swiftCode}", swiftHandler.GetSwiftCode)
-	router.Get("/v1/swift-codes/country/{countryISO2}", swiftHandler.GetSwiftCodesByCountry)
-	router.Post("/v1/swift-codes", swiftHandler.CreateSwiftCode)
-	router.Delete("/v1/swift-codes/{swiftCode}", swiftHandler.DeleteSwiftCode)
+	// Eksport strumieniuje cały katalog i może trwać dłużej niż zwykłe żądanie -
+	// jego zapytania ogranicza tylko limit na pojedynczą partię.
+	router.Get("/v1/swift-codes/export", swiftHandler.ExportSwiftCodes)
+	// Import pliku (także dry run, który porównuje go z całym katalogiem) trwa
+	// tyle, ile plik - tak jak swiftctl import przerywa go tylko zerwanie połączenia.
+	router.Group(func(r chi.Router) {
+		r.Use(handler.Audit)
+		r.Use(handler.Admin)
+		r.Post("/v1/admin/imports", adminHandler.StartImport)
+		r.Post("/v1/admin/imports/dry-run", adminHandler.DryRunImport)
+	})
+	router.Group(func(r chi.Router) {
+		r.Use(handler.Timeout(cfg.HTTP.RequestTimeout))
+		r.Use(handler.Audit)
+		r.Get("/v1/swift-codes/search", swiftHandler.SearchSwiftCodes)
+		r.Get("/v1/swift-codes/{swiftCode}", swiftHandler.GetSwiftCode)
+		r.Get("/v1/swift-codes/{swiftCode}/history", swiftHandler.GetSwiftCodeHistory)
+		r.Get("/v1/swift-codes/country/{countryISO2}", swiftHandler.GetSwiftCodesByCountry)
+		r.Get("/v1/countries", swiftHandler.ListCountries)
+		r.Get("/v1/countries/{countryISO2}", swiftHandler.GetCountry)
+		r.Get("/v1/banks/{institutionCode}", swiftHandler.GetBank)
+		r.Post("/v1/swift-codes", swiftHandler.CreateSwiftCode)
+		r.Post("/v1/swift-codes/lookup", swiftHandler.LookupSwiftCodes)
+		r.Post("/v1/swift-codes/bulk", swiftHandler.BulkCreateSwiftCodes)
+		r.Delete("/v1/swift-codes/bulk", swiftHandler.BulkDeleteSwiftCodes)
+		r.Put("/v1/swift-codes/{swiftCode}", swiftHandler.ReplaceSwiftCode)
+		r.Patch("/v1/swift-codes/{swiftCode}", swiftHandler.PatchSwiftCode)
+		r.Delete("/v1/swift-codes/{swiftCode}", swiftHandler.DeleteSwiftCode)
+		r.Post("/v1/swift-codes/{swiftCode}/restore", swiftHandler.RestoreSwiftCode)
+		r.Group(func(r chi.Router) {
+			r.Use(handler.Admin)
+			r.Get("/v1/admin/swift-codes/{swiftCode}", swiftHandler.GetSwiftCode)
+			r.Get("/v1/admin/swift-codes/{swiftCode}/history", swiftHandler.GetSwiftCodeHistory)
+			r.Get("/v1/admin/swift-codes/country/{countryISO2}", swiftHandler.GetSwiftCodesByCountry)
+			r.Get("/v1/admin/imports/{id}", adminHandler.GetImport)
+		})
+	})
 
 	log.Println("Starting HTTP server on :8080")
 	err = http.ListenAndServe(":8080", router)
@@ -52,3 +87,20 @@ func main() {
 		log.Fatalf("HTTP server error: %v", err)
 	}
 }
+
+func importOnStartup(cfg config.ImportConfig, swiftService service.SwiftService) {
+	ctx := repository.WithAudit(context.Background(), repository.Audit{Actor: "system", Source: repository.SourceImport})
+	report, err := importer.ImportFile(ctx, cfg.FilePath, cfg.Reader, cfg.Options, swiftService)
+	if err != nil {
+		log.Printf("IMPORT ERROR: %v", err)
+		if report != nil {
+			for _, rejected := range report.Rejected {
+				log.Printf("IMPORT ERROR: row %d, swiftCode=%s: %v", rejected.Row, rejected.SwiftCode, rejected.Errors)
+			}
+		}
+		return
+	}
+
+	log.Printf("Import finished (%s): %d rows, %d inserted, %d updated, %d unchanged, %d deleted, %d detached",
+		cfg.Options.Mode, report.TotalRows, report.Inserted, report.Updated, report.Unchanged, report.Deleted, report.Detached)
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/db"
+	"swift-codes-api/internal/exporter"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/repository"
+	"swift-codes-api/internal/service"
+)
+
+const usage = `swiftctl - SWIFT codes administration tool
+
+Usage:
+  swiftctl import   [-file path] [-format f] [-sheet name] [-columns mapping] [-delimiter c] [-encoding e] [-layout spec]
+                    [-mode upsert|sync] [-max-delete-percent N] [-dry-run] [-actor name]
+  swiftctl export   [-o path] [-format csv|xlsx|ndjson] [-country ISO2] [-hq true|false]
+  swiftctl migrate  up | down [-steps N] | version   [-path migrations]
+  swiftctl lookup   SWIFTCODE...
+  swiftctl purge    [-older-than duration] [-actor name]
+  swiftctl validate [-file path] [-format f] [-sheet name] [-columns mapping] [-delimiter c] [-encoding e] [-layout spec]
+
+Import formats: xlsx, csv, json, ndjson (default: detected from the file extension),
+bicplus (SWIFTRef BICPlus / BIC Directory, tab-separated) and fixed (fixed-width, requires -layout).
+
+Connection and import defaults are read from the same environment variables (.env) as the API.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+	command, args := os.Args[1], os.Args[2:]
+
+	// Ctrl+C przerywa trwające zapytania zamiast zostawiać je w bazie.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch command {
+	case "import":
+		err = runImport(ctx, cfg, args)
+	case "export":
+		err = runExport(ctx, cfg, args)
+	case "migrate":
+		err = runMigrate(cfg, args)
+	case "lookup":
+		err = runLookup(ctx, cfg, args)
+	case "validate":
+		err = runValidate(cfg, args)
+	case "purge":
+		err = runPurge(ctx, cfg, args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("%s: %v", command, err)
+	}
+}
+
+// importFlags rejestruje flagi wspólne dla import i validate; domyślne wartości
+// pochodzą z konfiguracji środowiska.
+func importFlags(fs *flag.FlagSet, cfg *config.ImportConfig) func() error {
+	fs.StringVar(&cfg.FilePath, "file", cfg.FilePath, "file to read (XLSX, CSV, JSON or NDJSON)")
+	format := fs.String("format", string(cfg.Reader.Format), "xlsx, csv, json, ndjson, bicplus or fixed (default: from file extension)")
+	fs.StringVar(&cfg.Reader.SheetName, "sheet", cfg.Reader.SheetName, "xlsx: sheet name (default: first sheet)")
+	columns := fs.String("columns", "", "xlsx/csv: header overrides, e.g. swiftCode=BIC,bankName=INSTITUTION NAME")
+	delimiter := fs.String("delimiter", "", "csv/bicplus: field delimiter, e.g. ';' or tab (default: ',')")
+	fs.StringVar(&cfg.Reader.Encoding, "encoding", cfg.Reader.Encoding, "csv/bicplus/fixed: text encoding, e.g. windows-1250 (default: UTF-8)")
+	layout := fs.String("layout", "", "fixed: column layout, e.g. bic8=1:8,branchCode=9:3,institutionName=12:105,countryName=117:35")
+
+	return func() error {
+		parsedFormat, err := importer.ParseFormat(*format)
+		if err != nil {
+			return err
+		}
+		cfg.Reader.Format = parsedFormat
+
+		if *delimiter != "" {
+			parsedDelimiter, err := importer.ParseDelimiter(*delimiter)
+			if err != nil {
+				return err
+			}
+			cfg.Reader.Delimiter = parsedDelimiter
+		}
+
+		if *layout != "" {
+			parsedLayout, err := importer.ParseFixedWidthLayout(*layout)
+			if err != nil {
+				return err
+			}
+			cfg.Reader.Layout = parsedLayout
+		}
+
+		if *columns == "" {
+			return nil
+		}
+		mapping, err := importer.ParseColumnMapping(*columns)
+		if err != nil {
+			return err
+		}
+		cfg.Reader.Columns = mapping
+		return nil
+	}
+}
+
+func openService(cfg config.Config) (service.SwiftService, *sql.DB, error) {
+	database, err := db.NewPostgresConnection(cfg.DB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+
+	return service.NewSwiftService(repository.NewSwiftRepository(database, cfg.DB.QueryTimeout)), database, nil
+}
+
+func runImport(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	importCfg := cfg.Import
+	parseReaderFlags := importFlags(fs, &importCfg)
+	mode := fs.String("mode", string(importCfg.Options.Mode), "import mode: upsert or sync")
+	fs.Float64Var(&importCfg.Options.MaxDeletePercent, "max-delete-percent", importCfg.Options.MaxDeletePercent, "sync: maximum share of rows that may be deleted")
+	dryRun := fs.Bool("dry-run", false, "print what would change without writing anything")
+	actor := fs.String("actor", os.Getenv("USER"), "author of the changes recorded in the history")
+	fs.Parse(args)
+
+	if err := parseReaderFlags(); err != nil {
+		return err
+	}
+	importMode, err := service.ParseImportMode(*mode)
+	if err != nil {
+		return err
+	}
+	importCfg.Options.Mode = importMode
+
+	records, err := importer.ReadFile(importCfg.FilePath, importCfg.Reader)
+	if err != nil {
+		return err
+	}
+
+	swiftService, database, err := openService(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if *dryRun {
+		diff, err := swiftService.DiffImport(ctx, records, importCfg.Options.Mode)
+		if err != nil {
+			return err
+		}
+		return printJSON(os.Stdout, diff)
+	}
+
+	ctx = repository.WithAudit(ctx, repository.Audit{Actor: *actor, Source: repository.SourceCLI})
+	report, err := swiftService.ImportSwiftCodes(ctx, records, importCfg.Options)
+	if report != nil {
+		if printErr := printJSON(os.Stdout, report); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
+
+func runExport(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "-", "output file (- for stdout)")
+	format := fs.String("format", "", "csv, xlsx or ndjson (default: from -o extension, xlsx for stdout)")
+	country := fs.String("country", "", "export only codes of this country (ISO2)")
+	hq := fs.String("hq", "", "true: only headquarters, false: only branches")
+	fs.Parse(args)
+
+	exportFormat := exporter.FormatXLSX
+	if *format == "" && *output != "-" {
+		*format = strings.TrimPrefix(filepath.Ext(*output), ".")
+	}
+	if *format != "" {
+		parsed, err := exporter.ParseFormat(*format)
+		if err != nil {
+			return err
+		}
+		exportFormat = parsed
+	}
+
+	opts := service.ExportOptions{CountryISO2: *country}
+	if *hq != "" {
+		value, err := strconv.ParseBool(*hq)
+		if err != nil {
+			return fmt.Errorf("invalid -hq value %q: %w", *hq, err)
+		}
+		opts.IsHeadquarter = &value
+	}
+
+	swiftService, database, err := openService(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	writer, err := exporter.NewWriter(w, exportFormat)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = swiftService.ExportSwiftCodes(ctx, opts, func(swift repository.SwiftCode) error {
+		count++
+		return writer.Write(swift)
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d swift codes", count)
+	return nil
+}
+
+func runMigrate(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected up, down or version")
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	path := fs.String("path", "migrations", "directory with migration files")
+	steps := fs.Int("steps", 1, "down: number of migrations to roll back")
+	fs.Parse(args[1:])
+
+	database, err := db.NewPostgresConnection(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("could not connect to database: %w", err)
+	}
+	defer database.Close()
+
+	switch args[0] {
+	case "up":
+		return db.RunMigrations(database, *path)
+	case "down":
+		if *steps < 1 {
+			return errors.New("-steps must be at least 1")
+		}
+		return db.RollbackMigrations(database, *path, *steps)
+	case "version":
+		version, dirty, err := db.MigrationVersion(database, *path)
+		if err != nil {
+			return err
+		}
+		status := strconv.FormatUint(uint64(version), 10)
+		if dirty {
+			status += " (dirty)"
+		}
+		fmt.Println(status)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or version", args[0])
+	}
+}
+
+func runLookup(ctx context.Context, cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected at least one swift code")
+	}
+
+	swiftService, database, err := openService(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	for _, code := range args {
+		result, err := swiftService.GetSwiftCodeWithBranches(ctx, code)
+		if err != nil {
+			return err
+		}
+		if err := printJSON(os.Stdout, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPurge fizycznie usuwa kody skasowane (soft delete) dawniej niż -older-than.
+func runPurge(ctx context.Context, cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	olderThan := fs.Duration("older-than", cfg.SoftDelete.Retention, "purge codes deleted longer ago than this, e.g. 720h")
+	actor := fs.String("actor", os.Getenv("USER"), "author of the changes recorded in the history")
+	fs.Parse(args)
+
+	swiftService, database, err := openService(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx = repository.WithAudit(ctx, repository.Audit{Actor: *actor, Source: repository.SourceCLI})
+	purged, err := swiftService.PurgeDeletedSwiftCodes(ctx, *olderThan)
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, struct {
+		Purged int `json:"purged"`
+	}{purged})
+}
+
+// runValidate sprawdza plik tymi samymi regułami co import, bez połączenia z bazą.
+func runValidate(cfg config.Config, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	importCfg := cfg.Import
+	parseReaderFlags := importFlags(fs, &importCfg)
+	fs.Parse(args)
+
+	if err := parseReaderFlags(); err != nil {
+		return err
+	}
+
+	records, err := importer.ReadFile(importCfg.FilePath, importCfg.Reader)
+	if err != nil {
+		return err
+	}
+
+	rejected := service.ValidateImportRecords(records)
+	if len(rejected) == 0 {
+		log.Printf("%s: all %d rows are valid", importCfg.FilePath, len(records))
+		return nil
+	}
+
+	if err := printJSON(os.Stdout, rejected); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d of %d rows are invalid", len(rejected), len(records))
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -4,12 +4,39 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"swift-codes-api/internal/db"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/service"
 )
 
-func LoadConfig() db.Config {
+type Config struct {
+	DB         db.Config
+	HTTP       HTTPConfig
+	Import     ImportConfig
+	SoftDelete SoftDeleteConfig
+}
+
+type HTTPConfig struct {
+	// RequestTimeout ogranicza czas obsługi żądania API (0 = bez limitu).
+	RequestTimeout time.Duration
+}
+
+type SoftDeleteConfig struct {
+	// Retention to czas, po którym swiftctl purge fizycznie usuwa skasowane kody.
+	Retention time.Duration
+}
+
+type ImportConfig struct {
+	OnStartup bool
+	FilePath  string
+	Reader    importer.Options
+	Options   service.ImportOptions
+}
+
+func LoadConfig() Config {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system env variables")
 	}
@@ -19,13 +46,88 @@ func LoadConfig() db.Config {
 		log.Fatalf("Invalid DB_PORT: %v", err)
 	}
 
-	return db.Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     port,
-		User:     getEnv("DB_USER", "swiftuser"),
-		Password: getEnv("DB_PASSWORD", "swiftpass"),
-		DBName:   getEnv("DB_NAME", "swiftcodesdb"),
-		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+	columns, err := importer.ParseColumnMapping(getEnv("IMPORT_COLUMNS", ""))
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_COLUMNS: %v", err)
+	}
+
+	importFormat, err := importer.ParseFormat(getEnv("IMPORT_FORMAT", ""))
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_FORMAT: %v", err)
+	}
+
+	delimiter, err := importer.ParseDelimiter(getEnv("IMPORT_CSV_DELIMITER", ""))
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_CSV_DELIMITER: %v", err)
+	}
+
+	layout, err := importer.ParseFixedWidthLayout(getEnv("IMPORT_FIXED_WIDTH_LAYOUT", ""))
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_FIXED_WIDTH_LAYOUT: %v", err)
+	}
+
+	importMode, err := service.ParseImportMode(getEnv("IMPORT_MODE", string(service.ImportModeUpsert)))
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_MODE: %v", err)
+	}
+
+	importOnStartup, err := strconv.ParseBool(getEnv("IMPORT_ON_STARTUP", "false"))
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_ON_STARTUP: %v", err)
+	}
+
+	maxDeletePercent, err := strconv.ParseFloat(getEnv("IMPORT_SYNC_MAX_DELETE_PERCENT", strconv.Itoa(service.DefaultMaxDeletePercent)), 64)
+	if err != nil {
+		log.Fatalf("Invalid IMPORT_SYNC_MAX_DELETE_PERCENT: %v", err)
+	}
+
+	queryTimeout, err := time.ParseDuration(getEnv("DB_QUERY_TIMEOUT", "5s"))
+	if err != nil {
+		log.Fatalf("Invalid DB_QUERY_TIMEOUT: %v", err)
+	}
+
+	requestTimeout, err := time.ParseDuration(getEnv("HTTP_REQUEST_TIMEOUT", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid HTTP_REQUEST_TIMEOUT: %v", err)
+	}
+
+	retention, err := time.ParseDuration(getEnv("SOFT_DELETE_RETENTION", "2160h"))
+	if err != nil {
+		log.Fatalf("Invalid SOFT_DELETE_RETENTION: %v", err)
+	}
+
+	return Config{
+		DB: db.Config{
+			Host:         getEnv("DB_HOST", "localhost"),
+			Port:         port,
+			User:         getEnv("DB_USER", "swiftuser"),
+			Password:     getEnv("DB_PASSWORD", "swiftpass"),
+			DBName:       getEnv("DB_NAME", "swiftcodesdb"),
+			SSLMode:      getEnv("DB_SSLMODE", "disable"),
+			QueryTimeout: queryTimeout,
+		},
+		HTTP: HTTPConfig{
+			RequestTimeout: requestTimeout,
+		},
+		Import: ImportConfig{
+			OnStartup: importOnStartup,
+			FilePath:  getEnv("IMPORT_FILE", "swift_data.xlsx"),
+			Reader: importer.Options{
+				Format:    importFormat,
+				SheetName: getEnv("IMPORT_SHEET", ""),
+				Columns:   columns,
+				Delimiter: delimiter,
+				Encoding:  getEnv("IMPORT_CSV_ENCODING", ""),
+				Layout:    layout,
+			},
+			Options: service.ImportOptions{
+				Mode:             importMode,
+				MaxDeletePercent: maxDeletePercent,
+			},
+		},
+		SoftDelete: SoftDeleteConfig{
+			Retention: retention,
+		},
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -18,6 +19,8 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// QueryTimeout ogranicza czas pojedynczego zapytania repozytorium (0 = bez limitu).
+	QueryTimeout time.Duration
 }
 
 func NewPostgresConnection(cfg Config) (*sql.DB, error) {
@@ -39,17 +42,26 @@ func NewPostgresConnection(cfg Config) (*sql.DB, error) {
 	return db, nil
 }
 
-func RunMigrations(db *sql.DB, migrationsPath string) error {
+func newMigrate(db *sql.DB, migrationsPath string) (*migrate.Migrate, error) {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("could not create postgres driver: %w", err)
+		return nil, fmt.Errorf("could not create postgres driver: %w", err)
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
 		fmt.Sprintf("file://%s", migrationsPath),
 		"postgres", driver)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return m, nil
+}
+
+func RunMigrations(db *sql.DB, migrationsPath string) error {
+	m, err := newMigrate(db, migrationsPath)
+	if err != nil {
+		return err
 	}
 
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
@@ -59,3 +71,37 @@ func RunMigrations(db *sql.DB, migrationsPath string) error {
 	log.Println("Database migrated successfully!")
 	return nil
 }
+
+// RollbackMigrations cofa podaną liczbę migracji.
+func RollbackMigrations(db *sql.DB, migrationsPath string, steps int) error {
+	m, err := newMigrate(db, migrationsPath)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Steps(-steps); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("migration rollback failed: %w", err)
+	}
+
+	log.Printf("Rolled back %d migration(s)", steps)
+	return nil
+}
+
+// MigrationVersion zwraca numer bieżącej migracji; dirty oznacza, że ostatnia
+// migracja nie zakończyła się poprawnie.
+func MigrationVersion(db *sql.DB, migrationsPath string) (version uint, dirty bool, err error) {
+	m, err := newMigrate(db, migrationsPath)
+	if err != nil {
+		return 0, false, err
+	}
+
+	version, dirty, err = m.Version()
+	if err == migrate.ErrNilVersion {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	return version, dirty, nil
+}
@@ -0,0 +1,241 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/repository"
+)
+
+const sheetName = "Sheet1"
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatXLSX   Format = "xlsx"
+	FormatNDJSON Format = "ndjson"
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(format))); f {
+	case FormatCSV, FormatXLSX, FormatNDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, expected csv, xlsx or ndjson", format)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Writer zapisuje kody jeden po drugim; Close dopisuje zakończenie pliku
+// i musi zostać wywołane, żeby wynik był kompletny.
+type Writer interface {
+	Write(swift repository.SwiftCode) error
+	Close() error
+}
+
+// NewWriter tworzy writer dla formatu. CSV i XLSX mają układ kolumn oczekiwany
+// przez importer, a NDJSON nazwy pól jak w API, więc każdy wynik można ponownie
+// zaimportować bez dodatkowego mapowania.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header()); err != nil {
+		return nil, fmt.Errorf("could not write csv header: %w", err)
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(swift repository.SwiftCode) error {
+	if err := c.w.Write(row(swift)); err != nil {
+		return fmt.Errorf("could not write csv row for %s: %w", swift.SwiftCode, err)
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return fmt.Errorf("could not write csv file: %w", err)
+	}
+	return nil
+}
+
+// xlsxWriter korzysta ze StreamWriter excelize, który nadmiar wierszy trzyma
+// w plikach tymczasowych zamiast w pamięci. Arkusz trafia do w dopiero w Close.
+type xlsxWriter struct {
+	w    io.Writer
+	f    *excelize.File
+	sw   *excelize.StreamWriter
+	next int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not create xlsx stream writer: %w", err)
+	}
+
+	x := &xlsxWriter{w: w, f: f, sw: sw, next: 1}
+	if err := x.setRow(header()); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not write xlsx header: %w", err)
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(swift repository.SwiftCode) error {
+	if err := x.setRow(row(swift)); err != nil {
+		return fmt.Errorf("could not write xlsx row for %s: %w", swift.SwiftCode, err)
+	}
+	return nil
+}
+
+func (x *xlsxWriter) setRow(values []string) error {
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+
+	cell, _ := excelize.CoordinatesToCellName(1, x.next)
+	x.next++
+	return x.sw.SetRow(cell, cells)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.f.Close()
+
+	if err := x.sw.Flush(); err != nil {
+		return fmt.Errorf("could not flush xlsx rows: %w", err)
+	}
+	if _, err := x.f.WriteTo(x.w); err != nil {
+		return fmt.Errorf("could not write xlsx file: %w", err)
+	}
+	return nil
+}
+
+// ndjsonRecord używa tych samych nazw pól co API i importer JSON.
+type ndjsonRecord struct {
+	SwiftCode            string  `json:"swiftCode"`
+	BankName             string  `json:"bankName"`
+	Address              string  `json:"address"`
+	TownName             string  `json:"townName"`
+	CountryISO2          string  `json:"countryISO2"`
+	CountryName          string  `json:"countryName"`
+	IsHeadquarter        bool    `json:"isHeadquarter"`
+	HeadquarterSwiftCode *string `json:"headquarterSwiftCode,omitempty"`
+	CodeType             string  `json:"codeType"`
+	TimeZone             string  `json:"timeZone"`
+	BranchInformation    string  `json:"branchInformation"`
+	ZipCode              string  `json:"zipCode"`
+	InstitutionType      string  `json:"institutionType"`
+}
+
+type ndjsonWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buf := bufio.NewWriter(w)
+	return &ndjsonWriter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (n *ndjsonWriter) Write(swift repository.SwiftCode) error {
+	record := ndjsonRecord{
+		SwiftCode:         swift.SwiftCode,
+		BankName:          swift.BankName,
+		Address:           swift.Address,
+		TownName:          swift.TownName,
+		CountryISO2:       swift.CountryISO2,
+		CountryName:       swift.CountryName,
+		IsHeadquarter:     swift.IsHeadquarter,
+		CodeType:          swift.CodeType,
+		TimeZone:          swift.TimeZone,
+		BranchInformation: swift.BranchInformation,
+		ZipCode:           swift.ZipCode,
+		InstitutionType:   swift.InstitutionType,
+	}
+	if swift.HeadquarterSwiftCode.Valid {
+		record.HeadquarterSwiftCode = &swift.HeadquarterSwiftCode.String
+	}
+
+	if err := n.enc.Encode(record); err != nil {
+		return fmt.Errorf("could not write ndjson line for %s: %w", swift.SwiftCode, err)
+	}
+	return nil
+}
+
+func (n *ndjsonWriter) Close() error {
+	if err := n.buf.Flush(); err != nil {
+		return fmt.Errorf("could not write ndjson file: %w", err)
+	}
+	return nil
+}
+
+func header() []string {
+	columns := importer.DefaultColumnMapping()
+	return []string{
+		columns.CountryISO2,
+		columns.SwiftCode,
+		columns.CodeType,
+		columns.BankName,
+		columns.Address,
+		columns.TownName,
+		columns.CountryName,
+		columns.TimeZone,
+		columns.BranchInformation,
+		columns.ZipCode,
+		columns.InstitutionType,
+	}
+}
+
+func row(swift repository.SwiftCode) []string {
+	return []string{
+		swift.CountryISO2,
+		swift.SwiftCode,
+		swift.CodeType,
+		swift.BankName,
+		swift.Address,
+		swift.TownName,
+		swift.CountryName,
+		swift.TimeZone,
+		swift.BranchInformation,
+		swift.ZipCode,
+		swift.InstitutionType,
+	}
+}
@@ -0,0 +1,115 @@
+package exporter
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/repository"
+	"swift-codes-api/internal/service"
+)
+
+type recordingSwiftService struct {
+	service.SwiftService
+	records []service.ImportRecord
+}
+
+func (r *recordingSwiftService) ImportSwiftCodes(ctx context.Context, records []service.ImportRecord, opts service.ImportOptions) (*service.ImportReport, error) {
+	r.records = records
+	return &service.ImportReport{TotalRows: len(records)}, nil
+}
+
+var testCodes = []repository.SwiftCode{
+	{
+		SwiftCode:       "BPKOPLPWXXX",
+		BankName:        "PKO BANK POLSKI",
+		Address:         "PULAWSKA 15",
+		TownName:        "WARSZAWA",
+		CountryISO2:     "PL",
+		CountryName:     "POLAND",
+		IsHeadquarter:   true,
+		CodeType:        "BIC11",
+		TimeZone:        "Europe/Warsaw",
+		InstitutionType: "BANK",
+	},
+	{
+		SwiftCode:            "BPKOPLPWKRA",
+		BankName:             "PKO BANK POLSKI",
+		Address:              "RYNEK 1, \"KAMIENICA\"",
+		TownName:             "KRAKÓW",
+		CountryISO2:          "PL",
+		CountryName:          "POLAND",
+		HeadquarterSwiftCode: sql.NullString{String: "BPKOPLPWXXX", Valid: true},
+		CodeType:             "BIC11",
+		BranchInformation:    "KRAKOW BRANCH",
+		ZipCode:              "31-042",
+	},
+}
+
+func writeFile(t *testing.T, format Format) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "swift_codes."+string(format))
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	writer, err := NewWriter(f, format)
+	assert.NoError(t, err)
+	for _, swift := range testCodes {
+		assert.NoError(t, writer.Write(swift))
+	}
+	assert.NoError(t, writer.Close())
+	return path
+}
+
+// assertRoundTrip sprawdza, że po ponownym imporcie serwis dostaje te same dane.
+func assertRoundTrip(t *testing.T, records []service.ImportRecord) {
+	t.Helper()
+	assert.Len(t, records, len(testCodes))
+	assert.Empty(t, service.ValidateImportRecords(records))
+
+	for i, swift := range testCodes {
+		in := records[i].Input
+		assert.Equal(t, swift.SwiftCode, in.SwiftCode)
+		assert.Equal(t, swift.BankName, in.BankName)
+		assert.Equal(t, swift.Address, in.Address)
+		assert.Equal(t, swift.TownName, in.TownName)
+		assert.Equal(t, swift.CountryISO2, in.CountryISO2)
+		assert.Equal(t, swift.CountryName, in.CountryName)
+		assert.Equal(t, swift.CodeType, in.CodeType)
+		assert.Equal(t, swift.TimeZone, in.TimeZone)
+		assert.Equal(t, swift.BranchInformation, in.BranchInformation)
+		assert.Equal(t, swift.ZipCode, in.ZipCode)
+		assert.Equal(t, swift.InstitutionType, in.InstitutionType)
+	}
+}
+
+func TestXLSXRoundTripsThroughImporter(t *testing.T) {
+	path := writeFile(t, FormatXLSX)
+
+	svc := &recordingSwiftService{}
+	_, err := importer.ImportSwiftCodesFromXLSX(context.Background(), path, importer.XLSXOptions{}, service.ImportOptions{}, svc)
+	assert.NoError(t, err)
+	assertRoundTrip(t, svc.records)
+}
+
+func TestCSVAndNDJSONRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatNDJSON} {
+		records, err := importer.ReadFile(writeFile(t, format), importer.Options{})
+		assert.NoError(t, err, format)
+		assertRoundTrip(t, records)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat(" XLSX ")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+}
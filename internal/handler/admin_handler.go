@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/repository"
+	"swift-codes-api/internal/service"
+)
+
+// maxUploadSize ogranicza rozmiar przesyłanego pliku katalogu BIC.
+const maxUploadSize = 32 << 20
+
+type AdminHandler struct {
+	service    service.SwiftService
+	readerOpts importer.Options
+	importOpts service.ImportOptions
+	jobs       *importer.JobManager
+}
+
+func NewAdminHandler(service service.SwiftService, readerOpts importer.Options, importOpts service.ImportOptions, jobs *importer.JobManager) *AdminHandler {
+	return &AdminHandler{service: service, readerOpts: readerOpts, importOpts: importOpts, jobs: jobs}
+}
+
+// StartImport przyjmuje plik (pole "file" formularza multipart) i uruchamia
+// jego import w tle. Zwraca 202 z identyfikatorem zadania; stan zadania
+// udostępnia GetImport.
+func (h *AdminHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	file, fileName, reader, ok := h.openUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	importOpts, err := h.importOptionsFromQuery(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "could not read uploaded file")
+		return
+	}
+
+	// Zmiany z importu trafiają do historii ze źródłem "import", ale z autorem żądania.
+	audit, _ := repository.AuditFromContext(r.Context())
+	audit.Source = repository.SourceImport
+	job, err := h.jobs.Start(repository.WithAudit(r.Context(), audit), fileName, data, reader, importOpts)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/v1/admin/imports/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *AdminHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.jobs.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeProblem(w, r, http.StatusNotFound, "import job not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// DryRunImport porównuje przesłany plik (pole "file" formularza multipart)
+// z bazą i zwraca raport zmian bez zapisywania czegokolwiek. Format wynika
+// z parametru "format" albo z rozszerzenia nazwy przesłanego pliku.
+func (h *AdminHandler) DryRunImport(w http.ResponseWriter, r *http.Request) {
+	file, _, reader, ok := h.openUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	importOpts, err := h.importOptionsFromQuery(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := reader.Read(file)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	diff, err := h.service.DiffImport(r.Context(), records, importOpts.Mode)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// openUpload otwiera przesłany plik i dobiera do niego czytnik; przy błędzie
+// odpowiedź jest już wysłana.
+func (h *AdminHandler) openUpload(w http.ResponseWriter, r *http.Request) (io.ReadCloser, string, importer.Reader, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "multipart field \"file\" with an XLSX, CSV or JSON file is required")
+		return nil, "", nil, false
+	}
+
+	opts, err := h.readerOptionsFromQuery(r)
+	if err != nil {
+		file.Close()
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return nil, "", nil, false
+	}
+
+	reader, err := importer.NewReader(header.Filename, opts)
+	if err != nil {
+		file.Close()
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return nil, "", nil, false
+	}
+
+	return file, header.Filename, reader, true
+}
+
+// importOptionsFromQuery pozwala wybrać tryb importu ("mode") i próg usuwania
+// ("maxDeletePercent") dla pojedynczego uploadu.
+func (h *AdminHandler) importOptionsFromQuery(r *http.Request) (service.ImportOptions, error) {
+	query := r.URL.Query()
+	opts := h.importOpts
+
+	if mode := query.Get("mode"); mode != "" {
+		parsed, err := service.ParseImportMode(mode)
+		if err != nil {
+			return opts, err
+		}
+		opts.Mode = parsed
+	}
+	if maxDelete := query.Get("maxDeletePercent"); maxDelete != "" {
+		parsed, err := strconv.ParseFloat(maxDelete, 64)
+		if err != nil {
+			return opts, err
+		}
+		opts.MaxDeletePercent = parsed
+	}
+
+	return opts, nil
+}
+
+// readerOptionsFromQuery nakłada parametry zapytania (format, sheet, delimiter,
+// encoding, layout) na domyślne ustawienia z konfiguracji. Format z konfiguracji dotyczy
+// pliku startowego, więc przy uploadzie decyduje parametr albo nazwa pliku.
+func (h *AdminHandler) readerOptionsFromQuery(r *http.Request) (importer.Options, error) {
+	query := r.URL.Query()
+	opts := h.readerOpts
+	opts.Format = ""
+
+	if format := query.Get("format"); format != "" {
+		parsed, err := importer.ParseFormat(format)
+		if err != nil {
+			return opts, err
+		}
+		opts.Format = parsed
+	}
+	if sheet := query.Get("sheet"); sheet != "" {
+		opts.SheetName = sheet
+	}
+	if delimiter := query.Get("delimiter"); delimiter != "" {
+		parsed, err := importer.ParseDelimiter(delimiter)
+		if err != nil {
+			return opts, err
+		}
+		opts.Delimiter = parsed
+	}
+	if encoding := query.Get("encoding"); encoding != "" {
+		opts.Encoding = encoding
+	}
+	if layout := query.Get("layout"); layout != "" {
+		parsed, err := importer.ParseFixedWidthLayout(layout)
+		if err != nil {
+			return opts, err
+		}
+		opts.Layout = parsed
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"swift-codes-api/internal/service"
+)
+
+type bulkItemResponse struct {
+	Index     int                  `json:"index"`
+	SwiftCode string               `json:"swiftCode"`
+	Status    int                  `json:"status"`
+	Message   string               `json:"message,omitempty"`
+	Errors    []service.FieldError `json:"errors,omitempty"`
+}
+
+type bulkResponse struct {
+	Mode      service.BulkMode   `json:"mode"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Items     []bulkItemResponse `json:"items"`
+}
+
+// BulkCreateSwiftCodes przyjmuje tablicę rekordów jak POST /v1/swift-codes.
+// Parametr "mode" wybiera tryb atomic (domyślny) albo bestEffort.
+func (h *SwiftHandler) BulkCreateSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	var requests []swiftCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body, expected an array of swift codes")
+		return
+	}
+
+	mode, err := service.ParseBulkMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	inputs := make([]service.CreateSwiftCodeInput, len(requests))
+	for i, req := range requests {
+		inputs[i] = req.toInput()
+	}
+
+	results, err := h.service.BulkCreateSwiftCodes(r.Context(), inputs, mode)
+	writeBulkResponse(w, r, mode, results, err, http.StatusCreated)
+}
+
+// BulkDeleteSwiftCodes przyjmuje tablicę kodów do usunięcia; parametr "cascade"
+// działa jak w DeleteSwiftCode.
+func (h *SwiftHandler) BulkDeleteSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	var codes []string
+	if err := json.NewDecoder(r.Body).Decode(&codes); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body, expected an array of swift codes")
+		return
+	}
+
+	mode, err := service.ParseBulkMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.BulkDeleteSwiftCodes(r.Context(), codes, mode, service.BranchPolicy(r.URL.Query().Get("cascade")))
+	writeBulkResponse(w, r, mode, results, err, http.StatusOK)
+}
+
+// writeBulkResponse odpowiada successStatus, gdy udały się wszystkie pozycje,
+// a 207 Multi-Status, gdy choć jedna zawiodła; status każdej pozycji jest w items.
+func writeBulkResponse(w http.ResponseWriter, r *http.Request, mode service.BulkMode, results []service.BulkItemResult, err error, successStatus int) {
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := bulkResponse{Mode: mode, Items: make([]bulkItemResponse, len(results))}
+	for i, result := range results {
+		item := bulkItemResponse{Index: result.Index, SwiftCode: result.SwiftCode, Status: successStatus}
+		if result.Err != nil {
+			item.Status, item.Message, item.Errors = bulkItemError(result.Err)
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Items[i] = item
+	}
+
+	status := successStatus
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// bulkItemError dobiera status pozycji tak samo jak writeError dla pojedynczego żądania.
+func bulkItemError(err error) (int, string, []service.FieldError) {
+	status := statusForError(err)
+	if status >= http.StatusInternalServerError {
+		log.Printf("[Bulk] %v", err)
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return status, publicErrorMessage(err, status), validationErr.Fields
+	}
+	return status, publicErrorMessage(err, status), nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"swift-codes-api/internal/repository"
+	"swift-codes-api/internal/service"
+)
+
+// anonymousActor trafia do historii zmian, gdy klient nie podał nagłówka X-Actor.
+const anonymousActor = "anonymous"
+
+const maxActorLength = 255
+
+// Timeout ogranicza czas obsługi żądania: po upływie d kontekst żądania jest
+// anulowany, co przerywa trwające zapytania, a writeError odpowiada 504.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type adminKey struct{}
+
+// Admin oznacza żądania tras /v1/admin. Dostęp do nich ogranicza się przed API
+// (bramka, sieć wewnętrzna), a handlery udostępniają tam dane ukryte na trasach
+// publicznych, np. usunięte kody (includeDeleted).
+func Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), adminKey{}, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func isAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// Audit przekazuje do repozytorium autora zmian z nagłówka X-Actor; trafia on
+// do historii rekordów razem ze źródłem "api".
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get("X-Actor"))
+		if actor == "" {
+			actor = anonymousActor
+		}
+		if len(actor) > maxActorLength {
+			verr := &service.ValidationError{Fields: []service.FieldError{{Field: "X-Actor", Message: fmt.Sprintf("must be at most %d characters", maxActorLength)}}}
+			writeError(w, r, verr)
+			return
+		}
+
+		ctx := repository.WithAudit(r.Context(), repository.Audit{Actor: actor, Source: repository.SourceAPI})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
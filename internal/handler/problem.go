@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"swift-codes-api/internal/repository"
+	"swift-codes-api/internal/service"
+)
+
+const problemContentType = "application/problem+json"
+
+// problem to odpowiedź błędu zgodna z RFC 7807. Errors to rozszerzenie
+// z błędami poszczególnych pól przy walidacji.
+type problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Errors   []service.FieldError `json:"errors,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblemDetails(w, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
+
+func writeProblemDetails(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeError zamienia błąd serwisu na odpowiedź problem+json. Błędy spoza
+// znanych kategorii trafiają do logu, a klient dostaje ogólny komunikat,
+// żeby nie ujawniać treści błędów bazy danych.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	// Klient się rozłączył - nie ma komu odpowiadać.
+	if errors.Is(r.Context().Err(), context.Canceled) {
+		return
+	}
+
+	status := statusForError(err)
+	if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+	p := problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   publicErrorMessage(err, status),
+		Instance: r.URL.Path,
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		p.Errors = validationErr.Fields
+	}
+	if status >= http.StatusInternalServerError {
+		log.Printf("[%s %s] %v", r.Method, r.URL.Path, err)
+	}
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+
+	writeProblemDetails(w, p)
+}
+
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrBulkRolledBack):
+		return http.StatusFailedDependency
+	case errors.Is(err, repository.ErrQueryTimeout):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func publicErrorMessage(err error, status int) string {
+	var validationErr *service.ValidationError
+	switch {
+	case status == http.StatusInternalServerError:
+		return "internal server error"
+	case status == http.StatusServiceUnavailable:
+		return "database query timed out, try again later"
+	case status == http.StatusGatewayTimeout:
+		return "request timed out"
+	case errors.As(err, &validationErr):
+		return "validation failed"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"swift-codes-api/internal/repository"
+	"swift-codes-api/internal/service"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+	}{
+		{
+			name:       "not found",
+			err:        fmt.Errorf("%w: BPKOPLPWXXX", service.ErrSwiftCodeNotFound),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "swift code not found: BPKOPLPWXXX",
+		},
+		{
+			name:       "conflict",
+			err:        fmt.Errorf("%w: BPKOPLPWXXX", service.ErrSwiftCodeExists),
+			wantStatus: http.StatusConflict,
+			wantDetail: "swift code already exists: BPKOPLPWXXX",
+		},
+		{
+			name:       "query timeout",
+			err:        fmt.Errorf("service error getting swift code: %w", repository.ErrQueryTimeout),
+			wantStatus: http.StatusServiceUnavailable,
+			wantDetail: "database query timed out, try again later",
+		},
+		{
+			name:       "database error is not leaked",
+			err:        fmt.Errorf("service error getting swift code: %w", errors.New("pq: relation \"swift.swift_codes\" does not exist")),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/BPKOPLPWXXX", nil)
+
+			writeError(w, r, tt.err)
+
+			var body problem
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantStatus, body.Status)
+			assert.Equal(t, http.StatusText(tt.wantStatus), body.Title)
+			assert.Equal(t, tt.wantDetail, body.Detail)
+			assert.Equal(t, "/v1/swift-codes/BPKOPLPWXXX", body.Instance)
+		})
+	}
+}
+
+func TestWriteErrorValidation(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", nil)
+	validationErr := &service.ValidationError{Fields: []service.FieldError{{Field: "bankName", Message: "is required"}}}
+
+	writeError(w, r, fmt.Errorf("wrapped: %w", validationErr))
+
+	var body problem
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "validation failed", body.Detail)
+	assert.Equal(t, validationErr.Fields, body.Errors)
+}
+
+func TestWriteErrorRequestTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/search", nil).WithContext(ctx)
+
+	writeError(w, r, errors.New("pq: canceling statement due to user request"))
+
+	var body problem
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "request timed out", body.Detail)
+}
+
+func TestTimeoutSetsRequestDeadline(t *testing.T) {
+	var hasDeadline bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	})
+
+	Timeout(time.Second)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, hasDeadline)
+}
+
+func TestAuditSetsActor(t *testing.T) {
+	var audit repository.Audit
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		audit, _ = repository.AuditFromContext(r.Context())
+	})
+
+	r := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/BPKOPLPWXXX", nil)
+	r.Header.Set("X-Actor", " jan.kowalski ")
+	Audit(next).ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, repository.Audit{Actor: "jan.kowalski", Source: repository.SourceAPI}, audit)
+
+	Audit(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, anonymousActor, audit.Actor)
+}
+
+func TestParseAsOf(t *testing.T) {
+	asOf, err := parseAsOf("2024-03-01T10:00:00+01:00")
+	assert.NoError(t, err)
+	assert.True(t, asOf.Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)))
+
+	asOf, err = parseAsOf("2024-03-01")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 23, 59, 59, 999999000, time.UTC), asOf)
+
+	_, err = parseAsOf("yesterday")
+	assert.Error(t, err)
+}
+
+func TestIncludeDeletedContext(t *testing.T) {
+	adminRequest := func(target string) *http.Request {
+		var req *http.Request
+		Admin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { req = r })).
+			ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		return req
+	}
+
+	req := adminRequest("/v1/admin/swift-codes/BPKOPLPWXXX?includeDeleted=false")
+	ctx, validationErr := includeDeletedContext(req)
+	assert.Nil(t, validationErr)
+	assert.Equal(t, req.Context(), ctx)
+
+	_, validationErr = includeDeletedContext(adminRequest("/v1/admin/swift-codes/BPKOPLPWXXX?includeDeleted=true"))
+	assert.Nil(t, validationErr)
+
+	_, validationErr = includeDeletedContext(adminRequest("/v1/admin/swift-codes/BPKOPLPWXXX?includeDeleted=maybe"))
+	assert.NotNil(t, validationErr)
+	assert.Equal(t, "includeDeleted", validationErr.Fields[0].Field)
+
+	// Na trasach publicznych usunięte kody nie są dostępne.
+	req = httptest.NewRequest(http.MethodGet, "/v1/swift-codes/BPKOPLPWXXX?includeDeleted=true", nil)
+	_, validationErr = includeDeletedContext(req)
+	assert.NotNil(t, validationErr)
+	assert.Equal(t, "includeDeleted", validationErr.Fields[0].Field)
+}
@@ -1,11 +1,19 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"swift-codes-api/internal/exporter"
+	"swift-codes-api/internal/repository"
 	"swift-codes-api/internal/service"
 )
 
@@ -20,9 +28,124 @@ func NewSwiftHandler(service service.SwiftService) *SwiftHandler {
 func (h *SwiftHandler) GetSwiftCode(w http.ResponseWriter, r *http.Request) {
 	swiftCodeParam := chi.URLParam(r, "swiftCode")
 
-	result, err := h.service.GetSwiftCodeWithBranches(swiftCodeParam)
+	ctx, validationErr := includeDeletedContext(r)
+	if validationErr != nil {
+		writeError(w, r, validationErr)
+		return
+	}
+
+	var result interface{}
+	var err error
+	if value := r.URL.Query().Get("asOf"); value != "" {
+		asOf, parseErr := parseAsOf(value)
+		if parseErr != nil {
+			writeError(w, r, &service.ValidationError{Fields: []service.FieldError{{Field: "asOf", Message: "must be an RFC 3339 timestamp or a date (YYYY-MM-DD)"}}})
+			return
+		}
+		result, err = h.service.GetSwiftCodeAsOf(ctx, swiftCodeParam, asOf)
+	} else {
+		result, err = h.service.GetSwiftCodeWithBranches(ctx, swiftCodeParam)
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *SwiftHandler) ListCountries(w http.ResponseWriter, r *http.Request) {
+	var includeEmpty bool
+	if value := r.URL.Query().Get("includeEmpty"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, r, &service.ValidationError{Fields: []service.FieldError{{Field: "includeEmpty", Message: "must be true or false"}}})
+			return
+		}
+		includeEmpty = parsed
+	}
+
+	result, err := h.service.ListCountries(r.Context(), includeEmpty)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *SwiftHandler) GetCountry(w http.ResponseWriter, r *http.Request) {
+	countryISO2 := chi.URLParam(r, "countryISO2")
+
+	result, err := h.service.GetCountry(r.Context(), countryISO2)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *SwiftHandler) GetBank(w http.ResponseWriter, r *http.Request) {
+	institutionCode := chi.URLParam(r, "institutionCode")
+
+	result, err := h.service.GetBank(r.Context(), institutionCode)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// includeDeletedContext obsługuje parametr includeDeleted: przy true odczyty
+// zwracają także kody usunięte (soft delete). Usunięte rekordy i ich autorów
+// widać tylko na trasach administracyjnych (Admin).
+func includeDeletedContext(r *http.Request) (context.Context, *service.ValidationError) {
+	value := r.URL.Query().Get("includeDeleted")
+	if value == "" {
+		return r.Context(), nil
+	}
+	if !isAdmin(r.Context()) {
+		return nil, &service.ValidationError{Fields: []service.FieldError{{Field: "includeDeleted", Message: "is only supported on /v1/admin routes"}}}
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, &service.ValidationError{Fields: []service.FieldError{{Field: "includeDeleted", Message: "must be true or false"}}}
+	}
+	if !include {
+		return r.Context(), nil
+	}
+	return repository.WithDeleted(r.Context()), nil
+}
+
+// parseAsOf przyjmuje znacznik czasu RFC 3339 albo samą datę, oznaczającą
+// stan na koniec tego dnia (UTC).
+func parseAsOf(value string) (time.Time, error) {
+	if asOf, err := time.Parse(time.RFC3339, value); err == nil {
+		return asOf, nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		return time.Time{}, err
+	}
+	return day.Add(24*time.Hour - time.Microsecond), nil
+}
+
+func (h *SwiftHandler) GetSwiftCodeHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, validationErr := includeDeletedContext(r)
+	if validationErr != nil {
+		writeError(w, r, validationErr)
+		return
+	}
+
+	result, err := h.service.GetSwiftCodeHistory(ctx, chi.URLParam(r, "swiftCode"))
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -33,45 +156,248 @@ func (h *SwiftHandler) GetSwiftCode(w http.ResponseWriter, r *http.Request) {
 func (h *SwiftHandler) GetSwiftCodesByCountry(w http.ResponseWriter, r *http.Request) {
 	countryISO2 := chi.URLParam(r, "countryISO2")
 
-	result, err := h.service.GetSwiftCodesByCountry(countryISO2)
+	opts, validationErr := countryListOptionsFromQuery(r.URL.Query())
+	if validationErr != nil {
+		writeError(w, r, validationErr)
+		return
+	}
+	ctx, validationErr := includeDeletedContext(r)
+	if validationErr != nil {
+		writeError(w, r, validationErr)
+		return
+	}
+
+	result, err := h.service.GetSwiftCodesByCountry(ctx, countryISO2, opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
+	result.Links = &service.PageLinks{Self: r.URL.RequestURI()}
+	if result.NextCursor != "" {
+		next := *r.URL
+		query := next.Query()
+		query.Set("cursor", result.NextCursor)
+		next.RawQuery = query.Encode()
+		result.Links.Next = next.RequestURI()
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
 
-func (h *SwiftHandler) CreateSwiftCode(w http.ResponseWriter, r *http.Request) {
-	var input struct {
-		SwiftCode            string  `json:"swiftCode"`
-		BankName             string  `json:"bankName"`
-		Address              string  `json:"address"`
-		CountryISO2          string  `json:"countryISO2"`
-		CountryName          string  `json:"countryName"`
-		IsHeadquarter        bool    `json:"isHeadquarter"`
-		HeadquarterSwiftCode *string `json:"headquarterSwiftCode"`
+func (h *SwiftHandler) SearchSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := service.SearchOptions{
+		Query:       query.Get("q"),
+		CountryISO2: query.Get("countryISO2"),
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&input)
+	validationErr := &service.ValidationError{}
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if opts.Limit, err = strconv.Atoi(limit); err != nil || opts.Limit < 1 {
+			validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: "limit", Message: "must be a positive integer"})
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if opts.Offset, err = strconv.Atoi(offset); err != nil || opts.Offset < 0 {
+			validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: "offset", Message: "must be a non-negative integer"})
+		}
+	}
+	if len(validationErr.Fields) > 0 {
+		writeError(w, r, validationErr)
+		return
+	}
+
+	result, err := h.service.SearchSwiftCodes(r.Context(), opts)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result.Links = &service.PageLinks{Self: r.URL.RequestURI()}
+	if nextOffset := result.Offset + len(result.Results); nextOffset < result.TotalCount {
+		next := *r.URL
+		query.Set("offset", strconv.Itoa(nextOffset))
+		next.RawQuery = query.Encode()
+		result.Links.Next = next.RequestURI()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+type lookupRequest struct {
+	SwiftCodes []string `json:"swiftCodes"`
+}
+
+// LookupSwiftCodes zwraca wiele kodów naraz: znalezione rekordy i listę kodów,
+// których nie ma w bazie.
+func (h *SwiftHandler) LookupSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	var req lookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.service.LookupSwiftCodes(r.Context(), req.SwiftCodes)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ExportSwiftCodes strumieniuje wszystkie kody (opcjonalnie z jednego kraju
+// albo tylko centrale/oddziały) w formacie csv (domyślnie), xlsx lub ndjson.
+func (h *SwiftHandler) ExportSwiftCodes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	validationErr := &service.ValidationError{}
+
+	format := exporter.FormatCSV
+	if value := query.Get("format"); value != "" {
+		parsed, err := exporter.ParseFormat(value)
+		if err != nil {
+			validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: "format", Message: "must be csv, xlsx or ndjson"})
+		}
+		format = parsed
+	}
+
+	opts := service.ExportOptions{CountryISO2: query.Get("countryISO2")}
+	if isHeadquarter := query.Get("isHeadquarter"); isHeadquarter != "" {
+		value, err := strconv.ParseBool(isHeadquarter)
+		if err != nil {
+			validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: "isHeadquarter", Message: "must be true or false"})
+		}
+		opts.IsHeadquarter = &value
+	}
+
+	if len(validationErr.Fields) > 0 {
+		writeError(w, r, validationErr)
+		return
+	}
+
+	out := &trackingWriter{w: w}
+	writer, err := exporter.NewWriter(out, format)
 	if err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	err = h.service.CreateSwiftCode(service.CreateSwiftCodeInput{
-		SwiftCode:            input.SwiftCode,
-		BankName:             input.BankName,
-		Address:              input.Address,
-		CountryISO2:          input.CountryISO2,
-		CountryName:          input.CountryName,
-		IsHeadquarter:        input.IsHeadquarter,
-		HeadquarterSwiftCode: input.HeadquarterSwiftCode,
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="swift_codes.%s"`, format))
+
+	err = h.service.ExportSwiftCodes(r.Context(), opts, func(swift repository.SwiftCode) error {
+		return writer.Write(swift)
 	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	// Po wysłaniu pierwszych bajtów nie da się już zmienić statusu odpowiedzi -
+	// klient dostanie urwany plik, a błąd trafia do logu.
+	if out.written {
+		log.Printf("[Export] aborted after partial response: %v", err)
+		return
+	}
+	w.Header().Del("Content-Disposition")
+	writeError(w, r, err)
+}
+
+// trackingWriter zapamiętuje, czy do klienta trafiło już cokolwiek.
+type trackingWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
 
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
+
+func countryListOptionsFromQuery(query url.Values) (service.CountryListOptions, *service.ValidationError) {
+	validationErr := &service.ValidationError{}
+	opts := service.CountryListOptions{
+		Cursor:         query.Get("cursor"),
+		Sort:           query.Get("sort"),
+		BankNamePrefix: query.Get("bankName"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: "limit", Message: "must be a positive integer"})
+		}
+		opts.Limit = value
+	}
+
+	if isHeadquarter := query.Get("isHeadquarter"); isHeadquarter != "" {
+		value, err := strconv.ParseBool(isHeadquarter)
+		if err != nil {
+			validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: "isHeadquarter", Message: "must be true or false"})
+		}
+		opts.IsHeadquarter = &value
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return opts, validationErr
+	}
+	return opts, nil
+}
+
+type swiftCodeRequest struct {
+	SwiftCode            string  `json:"swiftCode"`
+	BankName             string  `json:"bankName"`
+	Address              string  `json:"address"`
+	TownName             string  `json:"townName"`
+	CountryISO2          string  `json:"countryISO2"`
+	CountryName          string  `json:"countryName"`
+	IsHeadquarter        *bool   `json:"isHeadquarter"`
+	HeadquarterSwiftCode *string `json:"headquarterSwiftCode"`
+	CodeType             string  `json:"codeType"`
+	TimeZone             string  `json:"timeZone"`
+	BranchInformation    string  `json:"branchInformation"`
+	ZipCode              string  `json:"zipCode"`
+	InstitutionType      string  `json:"institutionType"`
+}
+
+func (req swiftCodeRequest) toInput() service.CreateSwiftCodeInput {
+	return service.CreateSwiftCodeInput{
+		SwiftCode:            req.SwiftCode,
+		BankName:             req.BankName,
+		Address:              req.Address,
+		TownName:             req.TownName,
+		CountryISO2:          req.CountryISO2,
+		CountryName:          req.CountryName,
+		IsHeadquarter:        req.IsHeadquarter,
+		HeadquarterSwiftCode: req.HeadquarterSwiftCode,
+		CodeType:             req.CodeType,
+		TimeZone:             req.TimeZone,
+		BranchInformation:    req.BranchInformation,
+		ZipCode:              req.ZipCode,
+		InstitutionType:      req.InstitutionType,
+	}
+}
+
+func (h *SwiftHandler) CreateSwiftCode(w http.ResponseWriter, r *http.Request) {
+	var input swiftCodeRequest
+
+	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err = h.service.CreateSwiftCode(r.Context(), input.toInput())
+
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -79,19 +405,152 @@ func (h *SwiftHandler) CreateSwiftCode(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"message":"Swift Code created successfully"}`))
 }
 
-func (h *SwiftHandler) DeleteSwiftCode(w http.ResponseWriter, r *http.Request) {
+func (h *SwiftHandler) ReplaceSwiftCode(w http.ResponseWriter, r *http.Request) {
 	swiftCodeParam := chi.URLParam(r, "swiftCode")
 
-	err := h.service.DeleteSwiftCode(swiftCodeParam)
+	var input swiftCodeRequest
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.service.ReplaceSwiftCode(r.Context(), swiftCodeParam, input.toInput())
 	if err != nil {
-		if err.Error() == fmt.Sprintf("swift code not found: %s", swiftCodeParam) {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
+		writeError(w, r, err)
+		return
+	}
+
+	message := "Swift Code replaced successfully"
+	status := http.StatusOK
+	if result.Created {
+		message = "Swift Code created successfully"
+		status = http.StatusCreated
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Message string `json:"message"`
+		*service.ReplaceSwiftCodeResult
+	}{
+		Message:                message,
+		ReplaceSwiftCodeResult: result,
+	})
+}
+
+// PatchSwiftCode obsługuje JSON Merge Patch (RFC 7396): pominięte pola pozostają
+// bez zmian, a null usuwa wartość (czyści pole albo przywraca wartość wyliczaną).
+func (h *SwiftHandler) PatchSwiftCode(w http.ResponseWriter, r *http.Request) {
+	swiftCodeParam := chi.URLParam(r, "swiftCode")
+
+	var patch map[string]json.RawMessage
+	err := json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil || patch == nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	input, validationErr := swiftCodePatchToInput(patch)
+	if validationErr != nil {
+		writeError(w, r, validationErr)
+		return
+	}
+
+	result, err := h.service.UpdateSwiftCode(r.Context(), swiftCodeParam, input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func swiftCodePatchToInput(patch map[string]json.RawMessage) (service.UpdateSwiftCodeInput, *service.ValidationError) {
+	var input service.UpdateSwiftCodeInput
+	validationErr := &service.ValidationError{}
+
+	stringFields := map[string]**string{
+		"swiftCode":            &input.SwiftCode,
+		"bankName":             &input.BankName,
+		"address":              &input.Address,
+		"townName":             &input.TownName,
+		"codeType":             &input.CodeType,
+		"timeZone":             &input.TimeZone,
+		"branchInformation":    &input.BranchInformation,
+		"zipCode":              &input.ZipCode,
+		"institutionType":      &input.InstitutionType,
+		"countryISO2":          &input.CountryISO2,
+		"countryName":          &input.CountryName,
+		"headquarterSwiftCode": &input.HeadquarterSwiftCode,
+	}
+
+	for field, value := range patch {
+		if target, ok := stringFields[field]; ok {
+			var v *string
+			if err := json.Unmarshal(value, &v); err != nil {
+				validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: field, Message: "must be a string or null"})
+				continue
+			}
+			if v == nil {
+				empty := ""
+				v = &empty
+			}
+			*target = v
+			continue
+		}
+
+		if field == "isHeadquarter" {
+			if err := json.Unmarshal(value, &input.IsHeadquarter); err != nil {
+				validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: field, Message: "must be a boolean or null"})
+			}
+			continue
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		validationErr.Fields = append(validationErr.Fields, service.FieldError{Field: field, Message: "unknown field"})
+	}
+
+	if len(validationErr.Fields) > 0 {
+		sort.Slice(validationErr.Fields, func(i, j int) bool {
+			return validationErr.Fields[i].Field < validationErr.Fields[j].Field
+		})
+		return input, validationErr
+	}
+	return input, nil
+}
+
+// DeleteSwiftCode usuwa kod. Parametr "cascade" decyduje o oddziałach usuwanej
+// centrali: restrict (domyślnie, 409 gdy istnieją), branches (usuwa je) albo detach.
+func (h *SwiftHandler) DeleteSwiftCode(w http.ResponseWriter, r *http.Request) {
+	swiftCodeParam := chi.URLParam(r, "swiftCode")
+	policy := service.BranchPolicy(r.URL.Query().Get("cascade"))
+
+	result, err := h.service.DeleteSwiftCode(r.Context(), swiftCodeParam, policy)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message":"Swift Code deleted successfully"}`))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Message string `json:"message"`
+		*service.DeleteResult
+	}{
+		Message:      "Swift Code deleted successfully",
+		DeleteResult: result,
+	})
+}
+
+func (h *SwiftHandler) RestoreSwiftCode(w http.ResponseWriter, r *http.Request) {
+	swiftCodeParam := chi.URLParam(r, "swiftCode")
+
+	result, err := h.service.RestoreSwiftCode(r.Context(), swiftCodeParam)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
 }
@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"swift-codes-api/internal/service"
+)
+
+// Pola pliku SWIFTRef BIC Directory / BICPlus. Nazwy są używane zarówno do
+// odszukania kolumn w nagłówku, jak i w opisie układu pliku o stałej szerokości.
+const (
+	bicFieldModificationFlag  = "modificationFlag"
+	bicFieldBIC               = "bic"
+	bicFieldBIC8              = "bic8"
+	bicFieldBranchCode        = "branchCode"
+	bicFieldInstitutionName   = "institutionName"
+	bicFieldBranchInformation = "branchInformation"
+	bicFieldCity              = "city"
+	bicFieldZipCode           = "zipCode"
+	bicFieldCountryName       = "countryName"
+	bicFieldCountryISO2       = "countryISO2"
+	bicFieldTimeZone          = "timeZone"
+	bicFieldSubtypeIndicator  = "subtypeIndicator"
+	bicFieldAddress1          = "address1"
+	bicFieldAddress2          = "address2"
+	bicFieldAddress3          = "address3"
+	bicFieldAddress4          = "address4"
+)
+
+// bicDirectoryHeaders to nagłówki akceptowane dla każdego pola: najpierw nazwy
+// z BICPlus, potem ze starszego BIC Directory.
+var bicDirectoryHeaders = map[string][]string{
+	bicFieldModificationFlag:  {"MODIFICATION FLAG"},
+	bicFieldBIC:               {"BIC", "BIC11"},
+	bicFieldBIC8:              {"BIC8", "BIC CODE"},
+	bicFieldBranchCode:        {"BRANCH BIC", "BRANCH CODE"},
+	bicFieldInstitutionName:   {"INSTITUTION NAME"},
+	bicFieldBranchInformation: {"BRANCH INFORMATION"},
+	bicFieldCity:              {"CITY", "CITY HEADING"},
+	bicFieldZipCode:           {"ZIP CODE"},
+	bicFieldCountryName:       {"COUNTRY NAME"},
+	bicFieldCountryISO2:       {"ISO COUNTRY CODE"},
+	bicFieldTimeZone:          {"TIMEZONE", "TIME ZONE"},
+	bicFieldSubtypeIndicator:  {"SUBTYPE INDICATOR"},
+	bicFieldAddress1:          {"STREET ADDRESS 1", "PHYSICAL ADDRESS 1"},
+	bicFieldAddress2:          {"STREET ADDRESS 2", "PHYSICAL ADDRESS 2"},
+	bicFieldAddress3:          {"STREET ADDRESS 3", "PHYSICAL ADDRESS 3"},
+	bicFieldAddress4:          {"STREET ADDRESS 4", "PHYSICAL ADDRESS 4"},
+}
+
+var bicAddressFields = []string{bicFieldAddress1, bicFieldAddress2, bicFieldAddress3, bicFieldAddress4}
+
+// BICPlusReader czyta pliki SWIFTRef BICPlus / BIC Directory rozdzielane
+// tabulatorami, z wierszem nagłówka. Flagi modyfikacji A i M dodają lub
+// aktualizują kod, D go usuwa, a U (bez zmian) jest importowana jak A.
+type BICPlusReader struct {
+	Delimiter rune
+	Encoding  string
+}
+
+func (b BICPlusReader) Read(r io.Reader) ([]service.ImportRecord, error) {
+	decoded, err := decodeText(r, b.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.Comma = '\t'
+	if b.Delimiter != 0 {
+		reader.Comma = b.Delimiter
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("bic directory file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read bic directory header: %w", err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToUpper(strings.TrimSpace(name))
+		if _, seen := positions[key]; !seen {
+			positions[key] = i
+		}
+	}
+	columns := make(map[string]int, len(bicDirectoryHeaders))
+	for field, names := range bicDirectoryHeaders {
+		for _, name := range names {
+			if pos, ok := positions[name]; ok {
+				columns[field] = pos
+				break
+			}
+		}
+	}
+	if err := checkBICDirectoryFields(columns, func(field string) string { return bicDirectoryHeaders[field][0] }); err != nil {
+		return nil, err
+	}
+
+	var records []service.ImportRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read bic directory: %w", err)
+		}
+		if isEmptyRow(row) {
+			continue
+		}
+
+		values := make(map[string]string, len(columns))
+		for field, pos := range columns {
+			if pos < len(row) {
+				values[field] = row[pos]
+			}
+		}
+
+		line, _ := reader.FieldPos(0)
+		record, err := bicDirectoryRecord(values, line)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// FieldPosition to położenie pola w wierszu o stałej szerokości; Start liczony
+// od 1, jak w specyfikacjach SWIFTRef.
+type FieldPosition struct {
+	Start  int
+	Length int
+}
+
+// FixedWidthLayout przypisuje polom BIC Directory ich położenie w wierszu.
+type FixedWidthLayout map[string]FieldPosition
+
+// ParseFixedWidthLayout czyta układ w postaci "bic8=1:8,branchCode=9:3,institutionName=12:105".
+func ParseFixedWidthLayout(spec string) (FixedWidthLayout, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	layout := FixedWidthLayout{}
+	for _, pair := range strings.Split(spec, ",") {
+		field, position, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		startText, lengthText, hasLength := strings.Cut(strings.TrimSpace(position), ":")
+		if !ok || !hasLength {
+			return nil, fmt.Errorf("invalid fixed-width layout %q, expected field=start:length", pair)
+		}
+		if _, known := bicDirectoryHeaders[field]; !known {
+			return nil, fmt.Errorf("unknown field %q in fixed-width layout", field)
+		}
+
+		start, startErr := strconv.Atoi(startText)
+		length, lengthErr := strconv.Atoi(lengthText)
+		if startErr != nil || lengthErr != nil || start < 1 || length < 1 {
+			return nil, fmt.Errorf("invalid position %q for field %q, expected positive start:length", position, field)
+		}
+		layout[field] = FieldPosition{Start: start, Length: length}
+	}
+
+	return layout, nil
+}
+
+// FixedWidthReader czyta pliki BIC Directory o stałej szerokości kolumn według
+// podanego układu. Wiersze krótsze niż układ są dopełniane pustymi wartościami.
+type FixedWidthReader struct {
+	Layout   FixedWidthLayout
+	Encoding string
+}
+
+func (f FixedWidthReader) Read(r io.Reader) ([]service.ImportRecord, error) {
+	if len(f.Layout) == 0 {
+		return nil, errors.New("fixed-width import requires a column layout")
+	}
+	if err := checkBICDirectoryFields(f.Layout, func(field string) string { return field }); err != nil {
+		return nil, err
+	}
+
+	decoded, err := decodeText(r, f.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(decoded)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var records []service.ImportRecord
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		values := make(map[string]string, len(f.Layout))
+		for field, pos := range f.Layout {
+			values[field] = substring(text, pos.Start-1, pos.Length)
+		}
+
+		record, err := bicDirectoryRecord(values, line)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read fixed-width file: %w", err)
+	}
+
+	return records, nil
+}
+
+// substring wycina znaki (nie bajty), bo nazwy instytucji zawierają znaki spoza ASCII.
+func substring(s string, start, length int) string {
+	runes := []rune(s)
+	if start >= len(runes) {
+		return ""
+	}
+	return string(runes[start:min(start+length, len(runes))])
+}
+
+// checkBICDirectoryFields sprawdza, czy plik pozwala ustalić pola wymagane przez
+// walidację serwisu: kod BIC, nazwę instytucji i nazwę kraju. Bez tego każdy
+// wiersz zostałby odrzucony osobno.
+func checkBICDirectoryFields[T any](columns map[string]T, name func(string) string) error {
+	var missing []string
+	_, hasBIC := columns[bicFieldBIC]
+	_, hasBIC8 := columns[bicFieldBIC8]
+	if !hasBIC && !hasBIC8 {
+		missing = append(missing, name(bicFieldBIC))
+	}
+	for _, field := range []string{bicFieldInstitutionName, bicFieldCountryName} {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, name(field))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return &MissingHeadersError{Headers: missing}
+	}
+	return nil
+}
+
+func bicDirectoryRecord(values map[string]string, row int) (service.ImportRecord, error) {
+	record := service.ImportRecord{Row: row}
+
+	switch flag := strings.ToUpper(strings.TrimSpace(values[bicFieldModificationFlag])); flag {
+	case "", "A", "M", "U":
+		record.Action = service.ImportActionUpsert
+	case "D":
+		record.Action = service.ImportActionDelete
+	default:
+		return record, fmt.Errorf("row %d: unknown modification flag %q, expected A, M, D or U", row, flag)
+	}
+
+	code := strings.TrimSpace(values[bicFieldBIC])
+	if code == "" {
+		code = strings.TrimSpace(values[bicFieldBIC8]) + strings.TrimSpace(values[bicFieldBranchCode])
+	}
+
+	// Plik nie zawiera kodu kraju wprost w starszym formacie - bierzemy go z BIC.
+	countryISO2 := values[bicFieldCountryISO2]
+	if strings.TrimSpace(countryISO2) == "" && len(code) >= 6 {
+		countryISO2 = code[4:6]
+	}
+
+	var address []string
+	for _, field := range bicAddressFields {
+		if line := strings.TrimSpace(values[field]); line != "" {
+			address = append(address, line)
+		}
+	}
+
+	record.Input = service.CreateSwiftCodeInput{
+		SwiftCode:         code,
+		BankName:          values[bicFieldInstitutionName],
+		Address:           strings.Join(address, ", "),
+		TownName:          values[bicFieldCity],
+		CountryISO2:       countryISO2,
+		CountryName:       values[bicFieldCountryName],
+		TimeZone:          values[bicFieldTimeZone],
+		BranchInformation: values[bicFieldBranchInformation],
+		ZipCode:           values[bicFieldZipCode],
+		InstitutionType:   values[bicFieldSubtypeIndicator],
+	}
+	return normalizeRecord(record), nil
+}
@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"swift-codes-api/internal/service"
+)
+
+// ColumnMapping wskazuje nagłówki kolumn pliku, z których pochodzą pola rekordu.
+type ColumnMapping struct {
+	CountryISO2 string
+	SwiftCode   string
+	CodeType    string
+	BankName    string
+	Address     string
+	TownName    string
+	CountryName string
+	TimeZone    string
+
+	BranchInformation string
+	ZipCode           string
+	InstitutionType   string
+}
+
+func DefaultColumnMapping() ColumnMapping {
+	return ColumnMapping{
+		CountryISO2: "COUNTRY ISO2 CODE",
+		SwiftCode:   "SWIFT CODE",
+		CodeType:    "CODE TYPE",
+		BankName:    "NAME",
+		Address:     "ADDRESS",
+		TownName:    "TOWN NAME",
+		CountryName: "COUNTRY NAME",
+		TimeZone:    "TIME ZONE",
+
+		BranchInformation: "BRANCH INFORMATION",
+		ZipCode:           "ZIP CODE",
+		InstitutionType:   "INSTITUTION TYPE",
+	}
+}
+
+// fields zwraca wskaźniki na nagłówki pod nazwami pól takimi jak w API.
+func (m *ColumnMapping) fields() map[string]*string {
+	return map[string]*string{
+		"countryISO2": &m.CountryISO2,
+		"swiftCode":   &m.SwiftCode,
+		"codeType":    &m.CodeType,
+		"bankName":    &m.BankName,
+		"address":     &m.Address,
+		"townName":    &m.TownName,
+		"countryName": &m.CountryName,
+		"timeZone":    &m.TimeZone,
+
+		"branchInformation": &m.BranchInformation,
+		"zipCode":           &m.ZipCode,
+		"institutionType":   &m.InstitutionType,
+	}
+}
+
+// requiredFields to pola, bez których import nie ma sensu.
+var requiredFields = []string{"countryISO2", "swiftCode", "bankName", "countryName"}
+
+// withDefaults uzupełnia nieustawione nagłówki wartościami domyślnymi.
+func (m ColumnMapping) withDefaults() ColumnMapping {
+	defaults := DefaultColumnMapping()
+	defaultFields := defaults.fields()
+	for field, header := range m.fields() {
+		if strings.TrimSpace(*header) == "" {
+			*header = *defaultFields[field]
+		}
+	}
+	return m
+}
+
+// ParseColumnMapping czyta mapowanie w postaci "swiftCode=BIC,bankName=INSTITUTION NAME".
+// Pominięte pola zachowują domyślne nagłówki.
+func ParseColumnMapping(spec string) (ColumnMapping, error) {
+	var m ColumnMapping
+	if strings.TrimSpace(spec) == "" {
+		return m.withDefaults(), nil
+	}
+
+	fields := m.fields()
+	for _, pair := range strings.Split(spec, ",") {
+		field, header, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		header = strings.TrimSpace(header)
+		if !ok || header == "" {
+			return m, fmt.Errorf("invalid column mapping %q, expected field=HEADER", pair)
+		}
+		target, known := fields[field]
+		if !known {
+			return m, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+		*target = header
+	}
+
+	return m.withDefaults(), nil
+}
+
+type MissingHeadersError struct {
+	Headers []string
+}
+
+func (e *MissingHeadersError) Error() string {
+	return fmt.Sprintf("missing required headers: %s", strings.Join(e.Headers, ", "))
+}
+
+// columnIndex to pozycje kolumn odnalezione w wierszu nagłówka (-1 = brak kolumny).
+type columnIndex map[string]int
+
+func resolveColumns(header []string, mapping ColumnMapping) (columnIndex, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToUpper(strings.TrimSpace(name))
+		if _, seen := positions[key]; !seen {
+			positions[key] = i
+		}
+	}
+
+	mapping = mapping.withDefaults()
+	index := columnIndex{}
+	for field, name := range mapping.fields() {
+		if pos, ok := positions[strings.ToUpper(strings.TrimSpace(*name))]; ok {
+			index[field] = pos
+		} else {
+			index[field] = -1
+		}
+	}
+
+	var missing []string
+	fields := mapping.fields()
+	for _, field := range requiredFields {
+		if index[field] < 0 {
+			missing = append(missing, *fields[field])
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, &MissingHeadersError{Headers: missing}
+	}
+
+	return index, nil
+}
+
+// value zwraca wartość pola z wiersza; excelize obcina puste komórki na końcu
+// wiersza, więc brakująca komórka oznacza pustą wartość.
+func (c columnIndex) value(row []string, field string) string {
+	pos := c[field]
+	if pos < 0 || pos >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[pos])
+}
+
+// record buduje rekord importu z wiersza o podanym numerze (numeracja jak w pliku).
+func (c columnIndex) record(row []string, rowNumber int) service.ImportRecord {
+	return normalizeRecord(service.ImportRecord{
+		Row: rowNumber,
+		Input: service.CreateSwiftCodeInput{
+			SwiftCode:   c.value(row, "swiftCode"),
+			CodeType:    c.value(row, "codeType"),
+			BankName:    c.value(row, "bankName"),
+			Address:     c.value(row, "address"),
+			TownName:    c.value(row, "townName"),
+			CountryISO2: c.value(row, "countryISO2"),
+			CountryName: c.value(row, "countryName"),
+			TimeZone:    c.value(row, "timeZone"),
+
+			BranchInformation: c.value(row, "branchInformation"),
+			ZipCode:           c.value(row, "zipCode"),
+			InstitutionType:   c.value(row, "institutionType"),
+		},
+	})
+}
+
+// normalizeRecord sprowadza rekordy ze wszystkich formatów do tej samej postaci.
+func normalizeRecord(record service.ImportRecord) service.ImportRecord {
+	in := &record.Input
+	in.SwiftCode = strings.TrimSpace(in.SwiftCode)
+	in.CodeType = strings.TrimSpace(in.CodeType)
+	in.BankName = strings.TrimSpace(in.BankName)
+	in.Address = strings.TrimSpace(in.Address)
+	in.TownName = strings.TrimSpace(in.TownName)
+	in.CountryISO2 = strings.ToUpper(strings.TrimSpace(in.CountryISO2))
+	in.CountryName = strings.ToUpper(strings.TrimSpace(in.CountryName))
+	in.TimeZone = strings.TrimSpace(in.TimeZone)
+	in.BranchInformation = strings.TrimSpace(in.BranchInformation)
+	in.ZipCode = strings.TrimSpace(in.ZipCode)
+	in.InstitutionType = strings.ToUpper(strings.TrimSpace(in.InstitutionType))
+	return record
+}
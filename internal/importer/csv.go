@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"swift-codes-api/internal/service"
+)
+
+// CSVReader czyta pliki CSV z wierszem nagłówka. Delimiter domyślnie to ',',
+// a Encoding (np. "windows-1250", "iso-8859-2") domyślnie UTF-8 z opcjonalnym BOM.
+type CSVReader struct {
+	Columns   ColumnMapping
+	Delimiter rune
+	Encoding  string
+}
+
+func (c CSVReader) Read(r io.Reader) ([]service.ImportRecord, error) {
+	decoded, err := decodeText(r, c.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(decoded)
+	reader.FieldsPerRecord = -1
+	if c.Delimiter != 0 {
+		reader.Comma = c.Delimiter
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv header: %w", err)
+	}
+
+	columns, err := resolveColumns(header, c.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	var records []service.ImportRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read csv: %w", err)
+		}
+		if isEmptyRow(row) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, columns.record(row, line))
+	}
+
+	return records, nil
+}
+
+func decodeText(r io.Reader, encoding string) (io.Reader, error) {
+	name := strings.ToLower(strings.TrimSpace(encoding))
+	if name == "" || name == "utf-8" || name == "utf8" {
+		return unicode.UTF8BOM.NewDecoder().Reader(r), nil
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding %q: %w", encoding, err)
+	}
+	return enc.NewDecoder().Reader(r), nil
+}
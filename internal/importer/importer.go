@@ -1,71 +1,100 @@
 package importer
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/xuri/excelize/v2"
 	"swift-codes-api/internal/service"
 )
 
-func ImportSwiftCodesFromXLSX(filePath string, swiftSvc service.SwiftService) error {
+// XLSXOptions pozwala wskazać arkusz i nagłówki kolumn; wartości zerowe
+// oznaczają pierwszy arkusz i domyślne nagłówki.
+type XLSXOptions struct {
+	SheetName string
+	Columns   ColumnMapping
+}
+
+// XLSXReader czyta skoroszyty Excela (excelize).
+type XLSXReader struct {
+	Options XLSXOptions
+}
+
+func (x XLSXReader) Read(r io.Reader) ([]service.ImportRecord, error) {
+	return ReadXLSXFrom(r, x.Options)
+}
+
+// ImportSwiftCodesFromXLSX wczytuje cały plik i przekazuje go do serwisu jako
+// jeden import: albo zapisane zostaną wszystkie wiersze, albo żaden.
+func ImportSwiftCodesFromXLSX(ctx context.Context, filePath string, opts XLSXOptions, importOpts service.ImportOptions, swiftSvc service.SwiftService) (*service.ImportReport, error) {
+	records, err := ReadXLSX(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return swiftSvc.ImportSwiftCodes(ctx, records, importOpts)
+}
+
+// ReadXLSX zamienia wiersze arkusza na rekordy importu, nie dotykając bazy danych.
+func ReadXLSX(filePath string, opts XLSXOptions) ([]service.ImportRecord, error) {
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
-		return fmt.Errorf("error opening xlsx file: %w", err)
+		return nil, fmt.Errorf("error opening xlsx file: %w", err)
 	}
 	defer f.Close()
 
-	rows, err := f.GetRows("Sheet1")
+	return readWorkbook(f, opts)
+}
+
+// ReadXLSXFrom działa jak ReadXLSX, ale czyta skoroszyt z dowolnego strumienia (np. z uploadu).
+func ReadXLSXFrom(r io.Reader, opts XLSXOptions) ([]service.ImportRecord, error) {
+	f, err := excelize.OpenReader(r)
 	if err != nil {
-		return fmt.Errorf("could not read rows from sheet: %w", err)
+		return nil, fmt.Errorf("error opening xlsx file: %w", err)
 	}
+	defer f.Close()
 
-	for i, row := range rows {
-		if i == 0 {
-			continue
-		}
-
-		if len(row) < 8 {
-			fmt.Printf("Skipping row with fewer columns than expected: %v\n", row)
-			continue
-		}
+	return readWorkbook(f, opts)
+}
 
-		countryISO2 := row[0]
-		swiftCode := row[1]
-		bankName := row[3]
-		address := row[4]
-		townName := row[5]
-		countryName := row[6]
+func readWorkbook(f *excelize.File, opts XLSXOptions) ([]service.ImportRecord, error) {
+	sheetName := opts.SheetName
+	if sheetName == "" {
+		sheetName = f.GetSheetName(0)
+	}
 
-		countryISO2 = strings.ToUpper(countryISO2)
-		countryName = strings.ToUpper(countryName)
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read rows from sheet %q: %w", sheetName, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sheet %q is empty", sheetName)
+	}
 
-		combinedAddress := fmt.Sprintf("%s, %s", address, townName)
+	columns, err := resolveColumns(rows[0], opts.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header in sheet %q: %w", sheetName, err)
+	}
 
-		isHeadquarter := false
-		if strings.HasSuffix(strings.ToUpper(swiftCode), "XXX") {
-			isHeadquarter = true
+	records := make([]service.ImportRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		if isEmptyRow(row) {
+			continue
 		}
 
-		var headquarterSwiftCode *string
-		if !isHeadquarter && len(swiftCode) >= 8 {
-			hq := swiftCode[:8] + "XXX"
-			headquarterSwiftCode = &hq
-		}
+		records = append(records, columns.record(row, i+2))
+	}
+
+	return records, nil
+}
 
-		err := swiftSvc.CreateSwiftCode(service.CreateSwiftCodeInput{
-			SwiftCode:            swiftCode,
-			BankName:             bankName,
-			Address:              combinedAddress,
-			CountryISO2:          countryISO2,
-			CountryName:          countryName,
-			IsHeadquarter:        isHeadquarter,
-			HeadquarterSwiftCode: headquarterSwiftCode,
-		})
-		if err != nil {
-			return fmt.Errorf("could not import row %d, swiftCode=%s: %w", i+1, swiftCode, err)
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
 		}
 	}
-
-	return nil
+	return true
 }
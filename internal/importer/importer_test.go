@@ -0,0 +1,274 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"swift-codes-api/internal/service"
+)
+
+type fakeSwiftService struct {
+	service.SwiftService
+	imported []service.ImportRecord
+}
+
+func (f *fakeSwiftService) ImportSwiftCodes(ctx context.Context, records []service.ImportRecord, opts service.ImportOptions) (*service.ImportReport, error) {
+	f.imported = append(f.imported, records...)
+	return &service.ImportReport{TotalRows: len(records), Inserted: len(records)}, nil
+}
+
+func writeXLSX(t *testing.T, sheet string, rows [][]interface{}) string {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if sheet != "Sheet1" {
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+		assert.NoError(t, f.DeleteSheet("Sheet1"))
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		assert.NoError(t, f.SetSheetRow(sheet, cell, &row))
+	}
+
+	path := filepath.Join(t.TempDir(), "codes.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	return path
+}
+
+func TestReadXLSX_ReorderedColumns(t *testing.T) {
+	path := writeXLSX(t, "Directory", [][]interface{}{
+		{"NAME", "SWIFT CODE", "TIME ZONE", "COUNTRY NAME", "TOWN NAME", "ADDRESS", "COUNTRY ISO2 CODE"},
+		{"PKO BANK POLSKI", "BPKOPLPWXXX", "Europe/Warsaw", "poland", "WARSZAWA", "PULAWSKA 15", "pl"},
+		{},
+		{"PKO BANK POLSKI", "BPKOPLPW123"},
+	})
+
+	records, err := ReadXLSX(path, XLSXOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 2, records[0].Row)
+	assert.Equal(t, 4, records[1].Row)
+
+	first := records[0].Input
+	assert.Equal(t, "BPKOPLPWXXX", first.SwiftCode)
+	assert.Equal(t, "PKO BANK POLSKI", first.BankName)
+	assert.Equal(t, "PULAWSKA 15", first.Address)
+	assert.Equal(t, "WARSZAWA", first.TownName)
+	assert.Equal(t, "PL", first.CountryISO2)
+	assert.Equal(t, "POLAND", first.CountryName)
+	assert.Equal(t, "Europe/Warsaw", first.TimeZone)
+	assert.Empty(t, first.CodeType)
+	assert.Empty(t, records[1].Input.CountryISO2)
+}
+
+func TestImportSwiftCodesFromXLSX_CustomMapping(t *testing.T) {
+	path := writeXLSX(t, "Sheet1", [][]interface{}{
+		{"ISO", "BIC", "INSTITUTION", "COUNTRY"},
+		{"PL", "BPKOPLPWXXX", "PKO BANK POLSKI", "POLAND"},
+	})
+
+	columns, err := ParseColumnMapping("countryISO2=ISO, swiftCode=BIC,bankName=INSTITUTION,countryName=country")
+	assert.NoError(t, err)
+
+	svc := &fakeSwiftService{}
+	report, err := ImportSwiftCodesFromXLSX(context.Background(), path, XLSXOptions{SheetName: "Sheet1", Columns: columns}, service.ImportOptions{}, svc)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Inserted)
+	assert.Len(t, svc.imported, 1)
+	assert.Equal(t, "PKO BANK POLSKI", svc.imported[0].Input.BankName)
+}
+
+func TestImportSwiftCodesFromXLSX_MissingHeaders(t *testing.T) {
+	path := writeXLSX(t, "Sheet1", [][]interface{}{
+		{"SWIFT CODE", "ADDRESS"},
+		{"BPKOPLPWXXX", "PULAWSKA 15"},
+	})
+
+	svc := &fakeSwiftService{}
+	_, err := ImportSwiftCodesFromXLSX(context.Background(), path, XLSXOptions{}, service.ImportOptions{}, svc)
+
+	var missingErr *MissingHeadersError
+	assert.ErrorAs(t, err, &missingErr)
+	assert.Equal(t, []string{"COUNTRY ISO2 CODE", "COUNTRY NAME", "NAME"}, missingErr.Headers)
+	assert.Empty(t, svc.imported)
+}
+
+func TestParseColumnMapping_Invalid(t *testing.T) {
+	_, err := ParseColumnMapping("swiftCode")
+	assert.Error(t, err)
+
+	_, err = ParseColumnMapping("iban=IBAN")
+	assert.Error(t, err)
+}
+
+func TestCSVReader_DelimiterAndEncoding(t *testing.T) {
+	// "ŁÓDŹ" w windows-1250
+	content := "SWIFT CODE;NAME;TOWN NAME;COUNTRY ISO2 CODE;COUNTRY NAME\r\n" +
+		"PKOPPLPWXXX;BANK PEKAO;\xa3\xd3D\x8f;pl;Poland\r\n" +
+		";;;;\r\n" +
+		"\"PKOPPLPW123\";\"BANK; PEKAO\";WARSZAWA;PL;POLAND\r\n"
+
+	reader := CSVReader{Delimiter: ';', Encoding: "windows-1250"}
+	records, err := reader.Read(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	assert.Equal(t, 2, records[0].Row)
+	assert.Equal(t, "ŁÓDŹ", records[0].Input.TownName)
+	assert.Equal(t, "PL", records[0].Input.CountryISO2)
+	assert.Equal(t, "POLAND", records[0].Input.CountryName)
+
+	assert.Equal(t, 4, records[1].Row)
+	assert.Equal(t, "BANK; PEKAO", records[1].Input.BankName)
+}
+
+func TestCSVReader_UTF8BOMAndMissingHeaders(t *testing.T) {
+	records, err := CSVReader{}.Read(strings.NewReader("\ufeffSWIFT CODE,NAME,COUNTRY ISO2 CODE,COUNTRY NAME\nPKOPPLPWXXX,BANK PEKAO,PL,POLAND\n"))
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "PKOPPLPWXXX", records[0].Input.SwiftCode)
+
+	_, err = CSVReader{}.Read(strings.NewReader("SWIFT CODE,NAME\n"))
+	var missing *MissingHeadersError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, []string{"COUNTRY ISO2 CODE", "COUNTRY NAME"}, missing.Headers)
+}
+
+func TestJSONReader_ArrayAndNDJSON(t *testing.T) {
+	array := `[
+		{"swiftCode": " pkopplpwxxx ", "bankName": "BANK PEKAO", "countryISO2": "pl", "countryName": "Poland"},
+		{"swiftCode": "PKOPPLPW123", "bankName": "BANK PEKAO", "countryISO2": "PL", "countryName": "POLAND", "isHeadquarter": false}
+	]`
+	records, err := JSONReader{}.Read(strings.NewReader(array))
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 1, records[0].Row)
+	assert.Equal(t, "pkopplpwxxx", records[0].Input.SwiftCode)
+	assert.Equal(t, "PL", records[0].Input.CountryISO2)
+	if assert.NotNil(t, records[1].Input.IsHeadquarter) {
+		assert.False(t, *records[1].Input.IsHeadquarter)
+	}
+
+	ndjson := "{\"swiftCode\":\"PKOPPLPWXXX\",\"bankName\":\"BANK PEKAO\"}\n\n{\"swiftCode\":\"PKOPPLPW123\"}\n"
+	records, err = JSONReader{NDJSON: true}.Read(strings.NewReader(ndjson))
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 3, records[1].Row)
+
+	_, err = JSONReader{}.Read(strings.NewReader(`{"swiftCode":"PKOPPLPWXXX"}`))
+	assert.Error(t, err)
+	_, err = JSONReader{NDJSON: true}.Read(strings.NewReader("{\"swiftCode\":\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestNewReader_DetectsFormat(t *testing.T) {
+	cases := map[string]Reader{
+		"codes.xlsx":  XLSXReader{},
+		"codes.CSV":   CSVReader{},
+		"codes.json":  JSONReader{},
+		"codes.jsonl": JSONReader{NDJSON: true},
+	}
+	for name, expected := range cases {
+		reader, err := NewReader(name, Options{})
+		assert.NoError(t, err, name)
+		assert.IsType(t, expected, reader, name)
+	}
+
+	reader, err := NewReader("codes.txt", Options{Format: FormatCSV, Delimiter: '\t'})
+	assert.NoError(t, err)
+	assert.Equal(t, CSVReader{Delimiter: '\t'}, reader)
+
+	_, err = NewReader("codes.txt", Options{})
+	assert.Error(t, err)
+	_, err = NewReader("codes", Options{})
+	assert.Error(t, err)
+}
+
+func TestImportFile_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "codes.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("SWIFT CODE,NAME,COUNTRY ISO2 CODE,COUNTRY NAME\nPKOPPLPWXXX,BANK PEKAO,PL,POLAND\n"), 0o644))
+
+	svc := &fakeSwiftService{}
+	report, err := ImportFile(context.Background(), path, Options{}, service.ImportOptions{}, svc)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Inserted)
+	assert.Equal(t, "PKOPPLPWXXX", svc.imported[0].Input.SwiftCode)
+}
+
+func TestBICPlusReader_ModificationFlags(t *testing.T) {
+	content := strings.Join([]string{
+		"MODIFICATION FLAG\tBIC8\tBRANCH BIC\tINSTITUTION NAME\tBRANCH INFORMATION\tCITY\tSTREET ADDRESS 1\tSTREET ADDRESS 2\tZIP CODE\tCOUNTRY NAME\tISO COUNTRY CODE\tSUBTYPE INDICATOR",
+		"A\tBPKOPLPW\tXXX\tPKO BANK POLSKI\t\tWARSZAWA\tPULAWSKA 15\t\t02-515\tPoland\tPL\tBANK",
+		"M\tBPKOPLPW\tKRA\tPKO BANK POLSKI\tKRAKOW BRANCH\tKRAKOW\tRYNEK 1\tPIETRO 2\t31-042\tPoland\tPL\tBANK",
+		"D\tBPKOPLPW\tGDA\tPKO BANK POLSKI\t\tGDANSK\t\t\t\tPoland\tPL\tBANK",
+	}, "\n")
+
+	records, err := BICPlusReader{}.Read(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, service.ImportActionUpsert, records[0].Action)
+	assert.Equal(t, "BPKOPLPWXXX", records[0].Input.SwiftCode)
+	assert.Equal(t, "02-515", records[0].Input.ZipCode)
+
+	branch := records[1].Input
+	assert.Equal(t, 3, records[1].Row)
+	assert.Equal(t, "BPKOPLPWKRA", branch.SwiftCode)
+	assert.Equal(t, "KRAKOW BRANCH", branch.BranchInformation)
+	assert.Equal(t, "RYNEK 1, PIETRO 2", branch.Address)
+	assert.Equal(t, "KRAKOW", branch.TownName)
+	assert.Equal(t, "POLAND", branch.CountryName)
+	assert.Equal(t, "BANK", branch.InstitutionType)
+
+	assert.Equal(t, service.ImportActionDelete, records[2].Action)
+	assert.Equal(t, "BPKOPLPWGDA", records[2].Input.SwiftCode)
+
+	_, err = BICPlusReader{}.Read(strings.NewReader("MODIFICATION FLAG\tBIC\tINSTITUTION NAME\tCOUNTRY NAME\nX\tBPKOPLPWXXX\tPKO\tPOLAND\n"))
+	assert.ErrorContains(t, err, "unknown modification flag")
+
+	_, err = BICPlusReader{}.Read(strings.NewReader("MODIFICATION FLAG\tCITY\n"))
+	var missing *MissingHeadersError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, []string{"BIC", "COUNTRY NAME", "INSTITUTION NAME"}, missing.Headers)
+}
+
+func TestFixedWidthReader(t *testing.T) {
+	layout, err := ParseFixedWidthLayout("modificationFlag=1:1,bic8=2:8,branchCode=10:3,institutionName=13:20,city=33:10,countryName=43:10")
+	assert.NoError(t, err)
+
+	content := "ABPKOPLPWXXXPKO BANK POLSKI     WARSZAWA  POLAND\n" +
+		"\n" +
+		"DBPKOPLPWGDA\n"
+
+	records, err := FixedWidthReader{Layout: layout}.Read(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "BPKOPLPWXXX", records[0].Input.SwiftCode)
+	assert.Equal(t, "PKO BANK POLSKI", records[0].Input.BankName)
+	assert.Equal(t, "WARSZAWA", records[0].Input.TownName)
+	assert.Equal(t, "PL", records[0].Input.CountryISO2)
+	assert.Equal(t, "POLAND", records[0].Input.CountryName)
+	assert.Equal(t, 3, records[1].Row)
+	assert.Equal(t, service.ImportActionDelete, records[1].Action)
+
+	_, err = ParseFixedWidthLayout("bic8=0:8")
+	assert.Error(t, err)
+	_, err = ParseFixedWidthLayout("swift=1:8")
+	assert.Error(t, err)
+	_, err = FixedWidthReader{}.Read(strings.NewReader(content))
+	assert.Error(t, err)
+
+	layout, err = ParseFixedWidthLayout("bic8=2:8,institutionName=13:20")
+	assert.NoError(t, err)
+	_, err = FixedWidthReader{Layout: layout}.Read(strings.NewReader(content))
+	var missing *MissingHeadersError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, []string{"countryName"}, missing.Headers)
+}
@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"swift-codes-api/internal/service"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// maxJobs ogranicza liczbę zapamiętanych zadań; najstarsze zakończone są usuwane.
+const maxJobs = 100
+
+// ImportJob to stan importu uruchomionego w tle. Report zawiera liczniki wierszy
+// i odrzucone wiersze, także gdy import został odrzucony w całości.
+type ImportJob struct {
+	ID         string                `json:"id"`
+	Status     JobStatus             `json:"status"`
+	FileName   string                `json:"fileName"`
+	Mode       service.ImportMode    `json:"mode"`
+	CreatedAt  time.Time             `json:"createdAt"`
+	StartedAt  *time.Time            `json:"startedAt,omitempty"`
+	FinishedAt *time.Time            `json:"finishedAt,omitempty"`
+	DurationMs *int64                `json:"durationMs,omitempty"`
+	Report     *service.ImportReport `json:"report,omitempty"`
+	Error      string                `json:"error,omitempty"`
+
+	done chan struct{}
+}
+
+// JobManager trzyma zadania importu w pamięci i wykonuje je po kolei,
+// żeby dwa importy (np. w trybie sync) nie nadpisywały się nawzajem.
+type JobManager struct {
+	service service.SwiftService
+
+	mu   sync.Mutex
+	jobs map[string]*ImportJob
+	run  sync.Mutex
+}
+
+func NewJobManager(service service.SwiftService) *JobManager {
+	return &JobManager{service: service, jobs: make(map[string]*ImportJob)}
+}
+
+// Start kolejkuje import pliku i od razu zwraca stan nowego zadania. Z ctx
+// zadanie przejmuje tylko wartości (np. autora zmian), nie jego anulowanie.
+func (m *JobManager) Start(ctx context.Context, fileName string, data []byte, reader Reader, opts service.ImportOptions) (ImportJob, error) {
+	mode, err := service.ParseImportMode(string(opts.Mode))
+	if err != nil {
+		return ImportJob{}, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return ImportJob{}, err
+	}
+
+	job := &ImportJob{
+		ID:        id,
+		Status:    JobQueued,
+		FileName:  fileName,
+		Mode:      mode,
+		CreatedAt: time.Now().UTC(),
+		done:      make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.prune()
+	m.jobs[id] = job
+	snapshot := *job
+	m.mu.Unlock()
+
+	// Zadanie żyje dłużej niż żądanie, które je utworzyło, więc nie dziedziczy jego anulowania.
+	go m.execute(context.WithoutCancel(ctx), job, data, reader, opts)
+	return snapshot, nil
+}
+
+// Get zwraca kopię stanu zadania, bezpieczną do serializacji poza blokadą.
+func (m *JobManager) Get(id string) (ImportJob, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return ImportJob{}, false
+	}
+	return *job, true
+}
+
+func (m *JobManager) execute(ctx context.Context, job *ImportJob, data []byte, reader Reader, opts service.ImportOptions) {
+	defer close(job.done)
+
+	m.run.Lock()
+	defer m.run.Unlock()
+
+	started := time.Now().UTC()
+	m.update(job, func(j *ImportJob) {
+		j.Status = JobRunning
+		j.StartedAt = &started
+	})
+
+	report, err := m.importFile(ctx, data, reader, opts)
+
+	finished := time.Now().UTC()
+	duration := finished.Sub(started).Milliseconds()
+	m.update(job, func(j *ImportJob) {
+		j.FinishedAt = &finished
+		j.DurationMs = &duration
+		j.Report = report
+		if err != nil {
+			j.Status = JobFailed
+			j.Error = err.Error()
+			return
+		}
+		j.Status = JobSucceeded
+	})
+}
+
+func (m *JobManager) importFile(ctx context.Context, data []byte, reader Reader, opts service.ImportOptions) (*service.ImportReport, error) {
+	records, err := reader.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return m.service.ImportSwiftCodes(ctx, records, opts)
+}
+
+func (m *JobManager) update(job *ImportJob, change func(*ImportJob)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change(job)
+}
+
+// prune usuwa najstarsze zakończone zadania ponad limit; wywoływane pod blokadą.
+func (m *JobManager) prune() {
+	if len(m.jobs) < maxJobs {
+		return
+	}
+
+	var finished []*ImportJob
+	for _, job := range m.jobs {
+		if job.Status == JobSucceeded || job.Status == JobFailed {
+			finished = append(finished, job)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].CreatedAt.Before(finished[j].CreatedAt) })
+
+	for _, job := range finished {
+		if len(m.jobs) < maxJobs {
+			return
+		}
+		delete(m.jobs, job.ID)
+	}
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New("could not generate import job id")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"swift-codes-api/internal/service"
+)
+
+type rejectingSwiftService struct {
+	service.SwiftService
+}
+
+func (rejectingSwiftService) ImportSwiftCodes(ctx context.Context, records []service.ImportRecord, opts service.ImportOptions) (*service.ImportReport, error) {
+	return &service.ImportReport{
+		TotalRows: len(records),
+		Rejected:  []service.RejectedRow{{Row: 2, SwiftCode: records[0].Input.SwiftCode}},
+	}, service.ErrImportRejected
+}
+
+func waitForJob(t *testing.T, m *JobManager, id string) ImportJob {
+	t.Helper()
+	m.mu.Lock()
+	done := m.jobs[id].done
+	m.mu.Unlock()
+	<-done
+
+	job, ok := m.Get(id)
+	assert.True(t, ok)
+	return job
+}
+
+func TestJobManager_Succeeded(t *testing.T) {
+	svc := &fakeSwiftService{}
+	m := NewJobManager(svc)
+
+	data := []byte("SWIFT CODE,NAME,COUNTRY ISO2 CODE,COUNTRY NAME\nPKOPPLPWXXX,BANK PEKAO,PL,POLAND\n")
+	started, err := m.Start(context.Background(), "codes.csv", data, CSVReader{}, service.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, JobQueued, started.Status)
+	assert.Equal(t, service.ImportModeUpsert, started.Mode)
+
+	job := waitForJob(t, m, started.ID)
+	assert.Equal(t, JobSucceeded, job.Status)
+	assert.Equal(t, 1, job.Report.Inserted)
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.FinishedAt)
+	assert.NotNil(t, job.DurationMs)
+	assert.Len(t, svc.imported, 1)
+
+	_, ok := m.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestJobManager_Failed(t *testing.T) {
+	m := NewJobManager(rejectingSwiftService{})
+
+	started, err := m.Start(context.Background(), "codes.json", []byte(`[{"swiftCode":"BPKO"}]`), JSONReader{}, service.ImportOptions{})
+	assert.NoError(t, err)
+	job := waitForJob(t, m, started.ID)
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Contains(t, job.Error, "import rejected")
+	assert.Len(t, job.Report.Rejected, 1)
+
+	started, err = m.Start(context.Background(), "codes.json", []byte(`{`), JSONReader{}, service.ImportOptions{})
+	assert.NoError(t, err)
+	job = waitForJob(t, m, started.ID)
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Nil(t, job.Report)
+
+	_, err = m.Start(context.Background(), "codes.json", nil, JSONReader{}, service.ImportOptions{Mode: "replace-all"})
+	assert.Error(t, err)
+}
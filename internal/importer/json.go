@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"swift-codes-api/internal/service"
+)
+
+// jsonRecord używa tych samych nazw pól co API.
+type jsonRecord struct {
+	SwiftCode            string  `json:"swiftCode"`
+	CodeType             string  `json:"codeType"`
+	BankName             string  `json:"bankName"`
+	Address              string  `json:"address"`
+	TownName             string  `json:"townName"`
+	CountryISO2          string  `json:"countryISO2"`
+	CountryName          string  `json:"countryName"`
+	TimeZone             string  `json:"timeZone"`
+	BranchInformation    string  `json:"branchInformation"`
+	ZipCode              string  `json:"zipCode"`
+	InstitutionType      string  `json:"institutionType"`
+	IsHeadquarter        *bool   `json:"isHeadquarter"`
+	HeadquarterSwiftCode *string `json:"headquarterSwiftCode"`
+}
+
+func (j jsonRecord) toImportRecord(row int) service.ImportRecord {
+	return normalizeRecord(service.ImportRecord{
+		Row: row,
+		Input: service.CreateSwiftCodeInput{
+			SwiftCode:            j.SwiftCode,
+			CodeType:             j.CodeType,
+			BankName:             j.BankName,
+			Address:              j.Address,
+			TownName:             j.TownName,
+			CountryISO2:          j.CountryISO2,
+			CountryName:          j.CountryName,
+			TimeZone:             j.TimeZone,
+			BranchInformation:    j.BranchInformation,
+			ZipCode:              j.ZipCode,
+			InstitutionType:      j.InstitutionType,
+			IsHeadquarter:        j.IsHeadquarter,
+			HeadquarterSwiftCode: j.HeadquarterSwiftCode,
+		},
+	})
+}
+
+// JSONReader czyta tablicę obiektów JSON albo, gdy NDJSON, jeden obiekt w każdej linii.
+// Numer wiersza to pozycja elementu w tablicy albo numer linii.
+type JSONReader struct {
+	NDJSON bool
+}
+
+func (j JSONReader) Read(r io.Reader) ([]service.ImportRecord, error) {
+	if j.NDJSON {
+		return readNDJSON(r)
+	}
+	return readJSONArray(r)
+}
+
+func readJSONArray(r io.Reader) ([]service.ImportRecord, error) {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("could not read json: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("json import must be an array of objects")
+	}
+
+	var records []service.ImportRecord
+	for row := 1; decoder.More(); row++ {
+		var record jsonRecord
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("could not decode json element %d: %w", row, err)
+		}
+		records = append(records, record.toImportRecord(row))
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("could not read json: %w", err)
+	}
+
+	return records, nil
+}
+
+func readNDJSON(r io.Reader) ([]service.ImportRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var records []service.ImportRecord
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var record jsonRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("could not decode ndjson line %d: %w", line, err)
+		}
+		records = append(records, record.toImportRecord(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read ndjson: %w", err)
+	}
+
+	return records, nil
+}
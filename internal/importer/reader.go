@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"swift-codes-api/internal/service"
+)
+
+// Reader zamienia plik źródłowy w dowolnym formacie na znormalizowane rekordy importu.
+type Reader interface {
+	Read(r io.Reader) ([]service.ImportRecord, error)
+}
+
+type Format string
+
+const (
+	FormatXLSX   Format = "xlsx"
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	// FormatBICPlus to pliki SWIFTRef BICPlus / BIC Directory rozdzielane tabulatorami.
+	FormatBICPlus Format = "bicplus"
+	// FormatFixedWidth to BIC Directory o stałej szerokości kolumn (wymaga FixedWidthLayout).
+	FormatFixedWidth Format = "fixed"
+)
+
+// Options wybiera czytnik i jego ustawienia. Pusty Format oznacza rozpoznanie
+// po rozszerzeniu pliku; pola nieistotne dla danego formatu są ignorowane.
+type Options struct {
+	Format    Format
+	SheetName string
+	Columns   ColumnMapping
+	Delimiter rune
+	Encoding  string
+	Layout    FixedWidthLayout
+}
+
+func (o Options) XLSX() XLSXOptions {
+	return XLSXOptions{SheetName: o.SheetName, Columns: o.Columns}
+}
+
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(format))); f {
+	case FormatXLSX, FormatCSV, FormatJSON, FormatNDJSON, FormatBICPlus, FormatFixedWidth:
+		return f, nil
+	case "jsonl":
+		return FormatNDJSON, nil
+	case "bic", "bicdir":
+		return FormatBICPlus, nil
+	case "":
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown import format %q, expected xlsx, csv, json, ndjson, bicplus or fixed", format)
+	}
+}
+
+// DetectFormat rozpoznaje format po rozszerzeniu nazwy pliku.
+func DetectFormat(fileName string) (Format, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	if ext == "" {
+		return "", fmt.Errorf("cannot detect import format of %q, specify it explicitly", fileName)
+	}
+	return ParseFormat(ext)
+}
+
+// NewReader zwraca czytnik dla formatu z opcji albo rozpoznanego z nazwy pliku.
+func NewReader(fileName string, opts Options) (Reader, error) {
+	format := opts.Format
+	if format == "" {
+		detected, err := DetectFormat(fileName)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+
+	switch format {
+	case FormatXLSX:
+		return XLSXReader{Options: opts.XLSX()}, nil
+	case FormatCSV:
+		return CSVReader{Columns: opts.Columns, Delimiter: opts.Delimiter, Encoding: opts.Encoding}, nil
+	case FormatJSON:
+		return JSONReader{}, nil
+	case FormatNDJSON:
+		return JSONReader{NDJSON: true}, nil
+	case FormatBICPlus:
+		return BICPlusReader{Delimiter: opts.Delimiter, Encoding: opts.Encoding}, nil
+	case FormatFixedWidth:
+		return FixedWidthReader{Layout: opts.Layout, Encoding: opts.Encoding}, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// ReadFile czyta plik wybranym czytnikiem, nie dotykając bazy danych.
+func ReadFile(filePath string, opts Options) ([]service.ImportRecord, error) {
+	reader, err := NewReader(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening import file: %w", err)
+	}
+	defer f.Close()
+
+	return reader.Read(f)
+}
+
+// ImportFile wczytuje plik w dowolnym obsługiwanym formacie i importuje go jako całość.
+func ImportFile(ctx context.Context, filePath string, opts Options, importOpts service.ImportOptions, swiftSvc service.SwiftService) (*service.ImportReport, error) {
+	records, err := ReadFile(filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return swiftSvc.ImportSwiftCodes(ctx, records, importOpts)
+}
+
+// ParseDelimiter zamienia nazwę lub znak separatora CSV na runę; "tab" i "\t" oznaczają tabulator.
+func ParseDelimiter(value string) (rune, error) {
+	switch value {
+	case "":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	runes := []rune(value)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("invalid csv delimiter %q, expected a single character", value)
+	}
+	return runes[0], nil
+}
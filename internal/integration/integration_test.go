@@ -39,7 +39,7 @@ func clearDB() {
 
 func TestCreateSwiftCode(t *testing.T) {
 	payload := map[string]interface{}{
-		"swiftCode":     "TESTCODE123",
+		"swiftCode":     "TESTPLPWXXX",
 		"bankName":      "Test Bank",
 		"address":       "Test Address",
 		"countryISO2":   "PL",
@@ -55,8 +55,25 @@ func TestCreateSwiftCode(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
+func TestCreateSwiftCode_Conflict(t *testing.T) {
+	payload := map[string]interface{}{
+		"swiftCode":   "TESTPLPWXXX",
+		"bankName":    "Other Bank",
+		"address":     "Other Address",
+		"countryISO2": "PL",
+		"countryName": "Poland",
+	}
+	body, _ := json.Marshal(payload)
+
+	resp, err := http.Post(baseURL, "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
 func TestGetSwiftCode_HQ(t *testing.T) {
-	resp, err := http.Get(baseURL + "/TESTCODE123")
+	resp, err := http.Get(baseURL + "/TESTPLPWXXX")
 	assert.NoError(t, err)
 	defer resp.Body.Close()
 
@@ -65,7 +82,7 @@ func TestGetSwiftCode_HQ(t *testing.T) {
 	var result map[string]interface{}
 	parseJSON(t, resp.Body, &result)
 
-	assert.Equal(t, "TESTCODE123", result["swiftCode"])
+	assert.Equal(t, "TESTPLPWXXX", result["swiftCode"])
 	assert.Equal(t, true, result["isHeadquarter"])
 	assert.Contains(t, result, "branches")
 }
@@ -87,7 +104,7 @@ func TestGetSwiftCodesByCountry(t *testing.T) {
 }
 
 func TestDeleteSwiftCode(t *testing.T) {
-	req, err := http.NewRequest(http.MethodDelete, baseURL+"/TESTCODE123", nil)
+	req, err := http.NewRequest(http.MethodDelete, baseURL+"/TESTPLPWXXX", nil)
 	assert.NoError(t, err)
 
 	client := &http.Client{}
@@ -98,6 +115,33 @@ func TestDeleteSwiftCode(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestCreateSwiftCode_AfterDelete(t *testing.T) {
+	// TESTPLPWXXX został usunięty w TestDeleteSwiftCode - usunięty kod można dodać ponownie.
+	payload := map[string]interface{}{
+		"swiftCode":   "TESTPLPWXXX",
+		"bankName":    "Recreated Bank",
+		"address":     "New Address",
+		"countryISO2": "PL",
+		"countryName": "Poland",
+	}
+	body, _ := json.Marshal(payload)
+
+	resp, err := http.Post(baseURL, "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, err = http.Get(baseURL + "/TESTPLPWXXX")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	parseJSON(t, resp.Body, &result)
+	assert.Equal(t, "Recreated Bank", result["bankName"])
+	assert.NotContains(t, result, "deletedAt")
+}
+
 func TestGetSwiftCode_NotFound(t *testing.T) {
 	resp, err := http.Get(baseURL + "/DOESNOTEXIST")
 	assert.NoError(t, err)
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Audit opisuje autora zmian zapisywanego w historii: Actor to użytkownik
+// (nagłówek X-Actor), a Source to kanał, którym przyszła zmiana.
+type Audit struct {
+	Actor  string
+	Source string
+}
+
+const (
+	SourceAPI    = "api"
+	SourceImport = "import"
+	SourceCLI    = "cli"
+)
+
+type auditKey struct{}
+
+// WithAudit dołącza do kontekstu autora zmian; repozytorium przekazuje go do
+// bazy na początku każdej transakcji.
+func WithAudit(ctx context.Context, audit Audit) context.Context {
+	return context.WithValue(ctx, auditKey{}, audit)
+}
+
+func AuditFromContext(ctx context.Context) (Audit, bool) {
+	audit, ok := ctx.Value(auditKey{}).(Audit)
+	return audit, ok
+}
+
+// setAudit ustawia zmienne czytane przez trigger historii. set_config z is_local
+// działa do końca transakcji, więc zmiany poza transakcją nie znają autora.
+func setAudit(ctx context.Context, tx *sql.Tx) error {
+	audit, ok := AuditFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	query := `SELECT set_config('swift.actor', $1, true), set_config('swift.source', $2, true)`
+	if _, err := tx.ExecContext(ctx, query, audit.Actor, audit.Source); err != nil {
+		return fmt.Errorf("failed to set audit context: %w", err)
+	}
+	return nil
+}
+
+// HistoryEntry to jedna zmiana rekordu; Old jest nil dla INSERT, New dla DELETE.
+type HistoryEntry struct {
+	ID        int64
+	SwiftCode string
+	Operation string
+	ChangedAt time.Time
+	Actor     string
+	Source    string
+	Old       *SwiftCode
+	New       *SwiftCode
+}
+
+// historyRecord to wiersz swift_codes zapisany przez trigger jako JSON.
+type historyRecord struct {
+	ID                   int        `json:"id"`
+	SwiftCode            string     `json:"swift_code"`
+	BankName             string     `json:"bank_name"`
+	Address              string     `json:"address"`
+	TownName             string     `json:"town_name"`
+	CountryISO2          string     `json:"country_iso2"`
+	CountryName          string     `json:"country_name"`
+	IsHeadquarter        bool       `json:"is_headquarter"`
+	HeadquarterSwiftCode *string    `json:"headquarter_swift_code"`
+	CodeType             string     `json:"code_type"`
+	TimeZone             string     `json:"time_zone"`
+	BranchInformation    string     `json:"branch_information"`
+	ZipCode              string     `json:"zip_code"`
+	InstitutionType      string     `json:"institution_type"`
+	DeletedAt            *time.Time `json:"deleted_at"`
+	DeletedBy            *string    `json:"deleted_by"`
+}
+
+func decodeHistoryRecord(data []byte) (*SwiftCode, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var rec historyRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode history record: %w", err)
+	}
+
+	swift := &SwiftCode{
+		ID:                rec.ID,
+		SwiftCode:         rec.SwiftCode,
+		BankName:          rec.BankName,
+		Address:           rec.Address,
+		TownName:          rec.TownName,
+		CountryISO2:       rec.CountryISO2,
+		CountryName:       rec.CountryName,
+		IsHeadquarter:     rec.IsHeadquarter,
+		CodeType:          rec.CodeType,
+		TimeZone:          rec.TimeZone,
+		BranchInformation: rec.BranchInformation,
+		ZipCode:           rec.ZipCode,
+		InstitutionType:   rec.InstitutionType,
+	}
+	if rec.HeadquarterSwiftCode != nil {
+		swift.HeadquarterSwiftCode = sql.NullString{String: *rec.HeadquarterSwiftCode, Valid: true}
+	}
+	if rec.DeletedAt != nil {
+		swift.DeletedAt = sql.NullTime{Time: *rec.DeletedAt, Valid: true}
+	}
+	if rec.DeletedBy != nil {
+		swift.DeletedBy = sql.NullString{String: *rec.DeletedBy, Valid: true}
+	}
+	return swift, nil
+}
+
+// GetHistory zwraca wszystkie zmiany kodu od najstarszej.
+func (r *swiftRepository) GetHistory(ctx context.Context, code string) (_ []HistoryEntry, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        SELECT id, swift_code, operation, changed_at, actor, source, old_values, new_values
+        FROM swift.swift_codes_history
+        WHERE swift_code = $1
+        ORDER BY changed_at, id
+    `
+	rows, err := r.q.QueryContext(ctx, query, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var entry HistoryEntry
+		var oldValues, newValues []byte
+		if err := rows.Scan(&entry.ID, &entry.SwiftCode, &entry.Operation, &entry.ChangedAt,
+			&entry.Actor, &entry.Source, &oldValues, &newValues); err != nil {
+			return nil, fmt.Errorf("failed to scan history entry: %w", err)
+		}
+		if entry.Old, err = decodeHistoryRecord(oldValues); err != nil {
+			return nil, err
+		}
+		if entry.New, err = decodeHistoryRecord(newValues); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetBySwiftCodeAsOf odtwarza rekord z ostatniej zmiany nie późniejszej niż asOf;
+// zwraca nil, jeśli kod wtedy nie istniał albo (bez WithDeleted) był usunięty.
+func (r *swiftRepository) GetBySwiftCodeAsOf(ctx context.Context, code string, asOf time.Time) (_ *SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        SELECT new_values
+        FROM swift.swift_codes_history
+        WHERE swift_code = $1 AND changed_at <= $2
+        ORDER BY changed_at DESC, id DESC
+        LIMIT 1
+    `
+	var newValues []byte
+	if err := r.q.QueryRowContext(ctx, query, code, asOf).Scan(&newValues); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get swift code as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+
+	swift, err := decodeHistoryRecord(newValues)
+	if err != nil || swift == nil {
+		return nil, err
+	}
+	if swift.DeletedAt.Valid && !IncludesDeleted(ctx) {
+		return nil, nil
+	}
+	return swift, nil
+}
+
+// GetBranchesAsOf zwraca oddziały centrali w stanie z chwili asOf. Oddziały mają
+// ten sam BIC8 co centrala, więc wystarczy przejrzeć historię kodów z tym prefiksem.
+func (r *swiftRepository) GetBranchesAsOf(ctx context.Context, hqCode string, asOf time.Time) (_ []SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        SELECT new_values
+        FROM (
+            SELECT DISTINCT ON (swift_code) swift_code, new_values
+            FROM swift.swift_codes_history
+            WHERE swift_code LIKE $2 AND changed_at <= $3
+            ORDER BY swift_code, changed_at DESC, id DESC
+        ) latest
+        WHERE new_values->>'headquarter_swift_code' = $1
+    `
+	if !IncludesDeleted(ctx) {
+		query += ` AND new_values->>'deleted_at' IS NULL`
+	}
+	query += ` ORDER BY swift_code`
+	rows, err := r.q.QueryContext(ctx, query, hqCode, escapeLike(hqCode[:8])+"%", asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query branches as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	var branches []SwiftCode
+	for rows.Next() {
+		var newValues []byte
+		if err := rows.Scan(&newValues); err != nil {
+			return nil, fmt.Errorf("failed to scan branch history: %w", err)
+		}
+		branch, err := decodeHistoryRecord(newValues)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, *branch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate branch history: %w", err)
+	}
+
+	return branches, nil
+}
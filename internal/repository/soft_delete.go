@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type includeDeletedKey struct{}
+
+// WithDeleted pozwala odczytom w tym kontekście zwracać także rekordy
+// oznaczone jako usunięte; domyślnie repozytorium je pomija.
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// IncludesDeleted mówi, czy kontekst powstał przez WithDeleted.
+func IncludesDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return include
+}
+
+// notDeleted to warunek WHERE odczytów, zależny od WithDeleted.
+func notDeleted(ctx context.Context) string {
+	if IncludesDeleted(ctx) {
+		return "TRUE"
+	}
+	return "deleted_at IS NULL"
+}
+
+// markDeleted to część SET oznaczająca rekord jako usunięty przez autora
+// przekazanego w transakcji (setAudit).
+const markDeleted = `deleted_at = now(),
+            deleted_by = COALESCE(NULLIF(current_setting('swift.actor', true), ''), session_user)`
+
+// RestoreSwiftCode przywraca usunięty rekord; sql.ErrNoRows oznacza, że
+// nie ma usuniętego rekordu o tym kodzie.
+func (r *swiftRepository) RestoreSwiftCode(ctx context.Context, code string) (err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        UPDATE swift.swift_codes
+        SET deleted_at = NULL, deleted_by = NULL
+        WHERE swift_code = $1 AND deleted_at IS NOT NULL
+    `
+	res, err := r.q.ExecContext(ctx, query, code)
+	if err != nil {
+		return fmt.Errorf("failed to restore swift code: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeDeleted fizycznie usuwa rekordy oznaczone jako usunięte przed before
+// i zwraca ich liczbę. Historia zmian tych kodów zostaje.
+func (r *swiftRepository) PurgeDeleted(ctx context.Context, before time.Time) (_ int, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	res, err := r.q.ExecContext(ctx, `DELETE FROM swift.swift_codes WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted swift codes: %w", err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(purged), nil
+}
@@ -1,9 +1,21 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrDuplicateSwiftCode      = errors.New("swift code already exists")
+	ErrDeleteThresholdExceeded = errors.New("delete threshold exceeded")
+	ErrQueryTimeout            = errors.New("database query timed out")
 )
 
 type SwiftCode struct {
@@ -11,47 +23,270 @@ type SwiftCode struct {
 	SwiftCode            string `json:"swiftCode"`
 	BankName             string `json:"bankName"`
 	Address              string `json:"address"`
+	TownName             string `json:"townName"`
 	CountryISO2          string `json:"countryISO2"`
 	CountryName          string `json:"countryName"`
 	IsHeadquarter        bool   `json:"isHeadquarter"`
 	HeadquarterSwiftCode sql.NullString
+	CodeType             string `json:"codeType"`
+	TimeZone             string `json:"timeZone"`
+	BranchInformation    string `json:"branchInformation"`
+	ZipCode              string `json:"zipCode"`
+	InstitutionType      string `json:"institutionType"`
+	DeletedAt            sql.NullTime
+	DeletedBy            sql.NullString
+}
+
+// SwiftCodeUpdate opisuje częściową aktualizację - zmieniane są tylko kolumny,
+// których pola nie są nil.
+type SwiftCodeUpdate struct {
+	BankName             *string
+	Address              *string
+	TownName             *string
+	CountryISO2          *string
+	CountryName          *string
+	IsHeadquarter        *bool
+	HeadquarterSwiftCode *sql.NullString
+	CodeType             *string
+	TimeZone             *string
+	BranchInformation    *string
+	ZipCode              *string
+	InstitutionType      *string
+}
+
+type SortField string
+
+const (
+	SortBySwiftCode SortField = "swiftCode"
+	SortByBankName  SortField = "bankName"
+)
+
+// CountryQuery opisuje stronę listy kodów kraju. Paginacja jest typu keyset:
+// kolejna strona zaczyna się za (AfterSortValue, AfterSwiftCode).
+type CountryQuery struct {
+	CountryISO2    string
+	IsHeadquarter  *bool
+	BankNamePrefix string
+	SortBy         SortField
+	AfterSortValue string
+	AfterSwiftCode string
+	Limit          int
+}
+
+type SearchQuery struct {
+	Text        string
+	CountryISO2 string
+	Limit       int
+	Offset      int
+}
+
+// ExportQuery zawęża eksport; wartości zerowe oznaczają wszystkie kody.
+type ExportQuery struct {
+	CountryISO2   string
+	IsHeadquarter *bool
+}
+
+// exportBatchSize to liczba wierszy pobieranych z kursora jednym FETCH.
+const exportBatchSize = 1000
+
+type SearchResult struct {
+	SwiftCode
+	Score float64
+}
+
+// ImportOptions steruje trybem synchronizacji: DeleteMissing usuwa kody, których
+// nie ma w importowanym zbiorze, o ile nie przekraczają MaxDeletePercent wszystkich wierszy.
+// DeleteCodes to kody jawnie oznaczone w pliku do usunięcia (flaga D w BIC Directory).
+type ImportOptions struct {
+	DeleteMissing    bool
+	MaxDeletePercent float64
+	DeleteCodes      []string
+}
+
+type ImportResult struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+	Deleted   int
+	Detached  int
+}
+
+// Country to wpis słownika krajów z liczbą central i oddziałów w bazie.
+type Country struct {
+	ISO2             string
+	ISO3             string
+	Name             string
+	HeadquarterCount int
+	BranchCount      int
+}
+
+type FieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"oldValue"`
+	NewValue interface{} `json:"newValue"`
 }
 
 type SwiftRepository interface {
-	GetBySwiftCode(code string) (*SwiftCode, error)
-	GetByCountryISO2(countryISO2 string) ([]SwiftCode, error)
-	GetBranchesByHeadquarterCode(hqCode string) ([]SwiftCode, error)
-	CreateSwiftCode(swift SwiftCode) error
-	DeleteBySwiftCode(code string) error
+	GetBySwiftCode(ctx context.Context, code string) (*SwiftCode, error)
+	GetBySwiftCodeForUpdate(ctx context.Context, code string) (*SwiftCode, error)
+	GetBySwiftCodes(ctx context.Context, codes []string) ([]SwiftCode, error)
+	GetByCountryISO2(ctx context.Context, q CountryQuery) ([]SwiftCode, error)
+	CountByCountryISO2(ctx context.Context, q CountryQuery) (int, error)
+	GetCountry(ctx context.Context, iso2 string) (*Country, error)
+	ListCountries(ctx context.Context, includeEmpty bool) ([]Country, error)
+	GetBranchesByHeadquarterCode(ctx context.Context, hqCode string) ([]SwiftCode, error)
+	GetByInstitutionCode(ctx context.Context, institutionCode string) ([]SwiftCode, error)
+	CountBranches(ctx context.Context, hqCode string) (int, error)
+	DeleteBranches(ctx context.Context, hqCode string) (int, error)
+	DetachBranches(ctx context.Context, hqCode string) (int, error)
+	GetHistory(ctx context.Context, code string) ([]HistoryEntry, error)
+	GetBySwiftCodeAsOf(ctx context.Context, code string, asOf time.Time) (*SwiftCode, error)
+	GetBranchesAsOf(ctx context.Context, hqCode string, asOf time.Time) ([]SwiftCode, error)
+	GetAll(ctx context.Context) ([]SwiftCode, error)
+	StreamSwiftCodes(ctx context.Context, q ExportQuery, fn func(SwiftCode) error) error
+	Search(ctx context.Context, q SearchQuery) ([]SearchResult, int, error)
+	CreateSwiftCode(ctx context.Context, swift SwiftCode) error
+	UpsertSwiftCode(ctx context.Context, swift SwiftCode) (bool, []FieldChange, error)
+	UpdateSwiftCode(ctx context.Context, code string, update SwiftCodeUpdate) error
+	DeleteBySwiftCode(ctx context.Context, code string) error
+	RestoreSwiftCode(ctx context.Context, code string) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+	ImportSwiftCodes(ctx context.Context, codes []SwiftCode, opts ImportOptions) (ImportResult, error)
+	WithTx(ctx context.Context, fn func(repo SwiftRepository) error) error
+}
+
+// dbtx to wspólna część *sql.DB i *sql.Tx, dzięki której te same metody
+// działają w transakcji i poza nią.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 type swiftRepository struct {
-	db *sql.DB
+	db           *sql.DB
+	q            dbtx
+	queryTimeout time.Duration
 }
 
-func NewSwiftRepository(db *sql.DB) SwiftRepository {
-	return &swiftRepository{db: db}
+// NewSwiftRepository tworzy repozytorium; queryTimeout (0 = bez limitu) ogranicza
+// czas pojedynczego zapytania niezależnie od kontekstu żądania.
+func NewSwiftRepository(db *sql.DB, queryTimeout time.Duration) SwiftRepository {
+	return &swiftRepository{db: db, q: db, queryTimeout: queryTimeout}
 }
 
-func (r *swiftRepository) GetBySwiftCode(code string) (*SwiftCode, error) {
-	query := `
-        SELECT id, swift_code, bank_name, address, country_iso2, country_name, is_headquarter, headquarter_swift_code
-        FROM swift.swift_codes
-        WHERE swift_code = $1
-    `
-	row := r.db.QueryRow(query, code)
+// queryContext nakłada limit czasu zapytania na ctx. Zwrócona funkcja, wywołana
+// przez defer, zwalnia kontekst i - jeśli zapytanie przerwano - dołącza do *err
+// przyczynę: ErrQueryTimeout albo błąd kontekstu żądania.
+func (r *swiftRepository) queryContext(ctx context.Context, err *error) (context.Context, func()) {
+	cancel := context.CancelFunc(func() {})
+	if r.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(ctx, r.queryTimeout, ErrQueryTimeout)
+	}
+
+	return ctx, func() {
+		*err = contextError(ctx, *err)
+		cancel()
+	}
+}
+
+// contextError dopisuje do błędu zapytania przyczynę przerwania kontekstu, bo
+// lib/pq zwraca wtedy własny błąd anulowania zamiast ctx.Err().
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(err, cause) {
+		return fmt.Errorf("%w: %w", cause, err)
+	}
+	return err
+}
+
+// WithTx wykonuje fn na repozytorium działającym w jednej transakcji;
+// błąd zwrócony przez fn wycofuje wszystkie zmiany. ImportSwiftCodes
+// i StreamSwiftCodes otwierają własne transakcje i nie należą do tej.
+// Autor zmian z kontekstu (WithAudit) trafia do historii tylko w transakcji.
+func (r *swiftRepository) WithTx(ctx context.Context, fn func(repo SwiftRepository) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setAudit(ctx, tx); err != nil {
+		return contextError(ctx, err)
+	}
+
+	if err := fn(&swiftRepository{db: r.db, q: tx, queryTimeout: r.queryTimeout}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return contextError(ctx, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	return nil
+}
+
+const swiftCodeColumns = `id, swift_code, bank_name, address, town_name, country_iso2, country_name, is_headquarter, headquarter_swift_code, code_type, time_zone,
+    branch_information, zip_code, institution_type, deleted_at, deleted_by`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanSwiftCode odczytuje kolumny swiftCodeColumns; extra to dodatkowe kolumny
+// wybrane za nimi w zapytaniu.
+func scanSwiftCode(row rowScanner, extra ...interface{}) (SwiftCode, error) {
 	var swift SwiftCode
-	err := row.Scan(
+	dest := []interface{}{
 		&swift.ID,
 		&swift.SwiftCode,
 		&swift.BankName,
 		&swift.Address,
+		&swift.TownName,
 		&swift.CountryISO2,
 		&swift.CountryName,
 		&swift.IsHeadquarter,
 		&swift.HeadquarterSwiftCode,
-	)
+		&swift.CodeType,
+		&swift.TimeZone,
+		&swift.BranchInformation,
+		&swift.ZipCode,
+		&swift.InstitutionType,
+		&swift.DeletedAt,
+		&swift.DeletedBy,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return swift, err
+}
+
+func scanSwiftCodes(rows *sql.Rows) ([]SwiftCode, error) {
+	var swiftCodes []SwiftCode
+	for rows.Next() {
+		swift, err := scanSwiftCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan swift code: %w", err)
+		}
+
+		swiftCodes = append(swiftCodes, swift)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate swift codes: %w", err)
+	}
+
+	return swiftCodes, nil
+}
+
+func (r *swiftRepository) GetBySwiftCode(ctx context.Context, code string) (_ *SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        SELECT ` + swiftCodeColumns + `
+        FROM swift.swift_codes
+        WHERE swift_code = $1 AND ` + notDeleted(ctx) + `
+    `
+	swift, err := scanSwiftCode(r.q.QueryRowContext(ctx, query, code))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -62,155 +297,690 @@ func (r *swiftRepository) GetBySwiftCode(code string) (*SwiftCode, error) {
 	return &swift, nil
 }
 
-func (r *swiftRepository) GetByCountryISO2(countryISO2 string) ([]SwiftCode, error) {
+// GetBySwiftCodes pobiera wiele kodów jednym zapytaniem; brakujące kody są pomijane.
+func (r *swiftRepository) GetBySwiftCodes(ctx context.Context, codes []string) (_ []SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
 	query := `
-        SELECT id, swift_code, bank_name, address, country_iso2, country_name, is_headquarter, headquarter_swift_code
+        SELECT ` + swiftCodeColumns + `
         FROM swift.swift_codes
-        WHERE country_iso2 = $1
+        WHERE swift_code = ANY($1) AND ` + notDeleted(ctx) + `
     `
-	rows, err := r.db.Query(query, countryISO2)
+	rows, err := r.q.QueryContext(ctx, query, pq.Array(codes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query swift codes: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSwiftCodes(rows)
+}
+
+// countryFilter buduje warunek WHERE wspólny dla listy i licznika kodów kraju.
+func countryFilter(ctx context.Context, q CountryQuery) (string, []interface{}) {
+	conditions := []string{"country_iso2 = $1", notDeleted(ctx)}
+	args := []interface{}{q.CountryISO2}
+
+	if q.IsHeadquarter != nil {
+		args = append(args, *q.IsHeadquarter)
+		conditions = append(conditions, fmt.Sprintf("is_headquarter = $%d", len(args)))
+	}
+	if q.BankNamePrefix != "" {
+		args = append(args, escapeLike(q.BankNamePrefix)+"%")
+		conditions = append(conditions, fmt.Sprintf("bank_name ILIKE $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *swiftRepository) GetByCountryISO2(ctx context.Context, q CountryQuery) (_ []SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	where, args := countryFilter(ctx, q)
+
+	orderBy := "swift_code"
+	if q.SortBy == SortByBankName {
+		orderBy = "bank_name, swift_code"
+		if q.AfterSwiftCode != "" {
+			args = append(args, q.AfterSortValue, q.AfterSwiftCode)
+			where += fmt.Sprintf(" AND (bank_name, swift_code) > ($%d, $%d)", len(args)-1, len(args))
+		}
+	} else if q.AfterSwiftCode != "" {
+		args = append(args, q.AfterSwiftCode)
+		where += fmt.Sprintf(" AND swift_code > $%d", len(args))
+	}
+
+	args = append(args, q.Limit)
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM swift.swift_codes
+        WHERE %s
+        ORDER BY %s
+        LIMIT $%d
+    `, swiftCodeColumns, where, orderBy, len(args))
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query swift codes by country: %w", err)
 	}
 	defer rows.Close()
 
-	var swiftCodes []SwiftCode
+	return scanSwiftCodes(rows)
+}
+
+// CountByCountryISO2 zwraca liczbę kodów spełniających filtry (bez kursora).
+func (r *swiftRepository) CountByCountryISO2(ctx context.Context, q CountryQuery) (_ int, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	where, args := countryFilter(ctx, q)
+	query := fmt.Sprintf(`
+        SELECT COUNT(*)
+        FROM swift.swift_codes
+        WHERE %s
+    `, where)
+
+	var total int
+	if err := r.q.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count swift codes by country: %w", err)
+	}
+
+	return total, nil
+}
+
+const countryStatsQuery = `
+        SELECT c.iso2, c.iso3, c.name,
+               COUNT(s.id) FILTER (WHERE s.is_headquarter),
+               COUNT(s.id) FILTER (WHERE NOT s.is_headquarter)
+        FROM swift.countries c
+        LEFT JOIN swift.swift_codes s ON s.country_iso2 = c.iso2 AND s.deleted_at IS NULL
+`
+
+// GetCountry zwraca kraj ze słownika albo nil, jeśli kodu ISO nie ma w słowniku.
+func (r *swiftRepository) GetCountry(ctx context.Context, iso2 string) (_ *Country, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := countryStatsQuery + `
+        WHERE c.iso2 = $1
+        GROUP BY c.iso2
+    `
+	var c Country
+	err = r.q.QueryRowContext(ctx, query, iso2).Scan(&c.ISO2, &c.ISO3, &c.Name, &c.HeadquarterCount, &c.BranchCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get country: %w", err)
+	}
+
+	return &c, nil
+}
+
+// ListCountries zwraca kraje posortowane po kodzie ISO; bez includeEmpty
+// tylko te, które mają co najmniej jeden kod SWIFT.
+func (r *swiftRepository) ListCountries(ctx context.Context, includeEmpty bool) (_ []Country, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := countryStatsQuery + `
+        GROUP BY c.iso2
+    `
+	if !includeEmpty {
+		query += ` HAVING COUNT(s.id) > 0`
+	}
+	query += ` ORDER BY c.iso2`
+
+	rows, err := r.q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query countries: %w", err)
+	}
+	defer rows.Close()
+
+	var countries []Country
 	for rows.Next() {
-		var swift SwiftCode
-		err := rows.Scan(
-			&swift.ID,
-			&swift.SwiftCode,
-			&swift.BankName,
-			&swift.Address,
-			&swift.CountryISO2,
-			&swift.CountryName,
-			&swift.IsHeadquarter,
-			&swift.HeadquarterSwiftCode,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan swift code: %w", err)
+		var c Country
+		if err := rows.Scan(&c.ISO2, &c.ISO3, &c.Name, &c.HeadquarterCount, &c.BranchCount); err != nil {
+			return nil, fmt.Errorf("failed to scan country: %w", err)
 		}
+		countries = append(countries, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate countries: %w", err)
+	}
 
-		swiftCodes = append(swiftCodes, swift)
+	return countries, nil
+}
+
+func (r *swiftRepository) GetBranchesByHeadquarterCode(ctx context.Context, hqCode string) (_ []SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        SELECT ` + swiftCodeColumns + `
+        FROM swift.swift_codes
+        WHERE headquarter_swift_code = $1 AND ` + notDeleted(ctx) + `
+        ORDER BY swift_code
+    `
+
+	rows, err := r.q.QueryContext(ctx, query, hqCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query branches: %w", err)
 	}
+	defer rows.Close()
 
-	return swiftCodes, nil
+	return scanSwiftCodes(rows)
 }
 
-func (r *swiftRepository) GetBranchesByHeadquarterCode(hqCode string) ([]SwiftCode, error) {
+// GetByInstitutionCode zwraca wszystkie kody z tym samym kodem instytucji
+// (pierwsze 4 znaki), posortowane po kodzie - czyli po kraju i lokalizacji.
+func (r *swiftRepository) GetByInstitutionCode(ctx context.Context, institutionCode string) (_ []SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
 	query := `
-        SELECT id, swift_code, bank_name, address, country_iso2, country_name, is_headquarter, headquarter_swift_code
+        SELECT ` + swiftCodeColumns + `
         FROM swift.swift_codes
+        WHERE left(swift_code, 4) = $1 AND ` + notDeleted(ctx) + `
+        ORDER BY swift_code
+    `
+	rows, err := r.q.QueryContext(ctx, query, institutionCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query swift codes by institution: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSwiftCodes(rows)
+}
+
+func (r *swiftRepository) CountBranches(ctx context.Context, hqCode string) (_ int, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	var count int
+	query := `SELECT COUNT(*) FROM swift.swift_codes WHERE headquarter_swift_code = $1 AND deleted_at IS NULL`
+	if err := r.q.QueryRowContext(ctx, query, hqCode).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count branches: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteBranches oznacza jako usunięte wszystkie oddziały centrali i zwraca ich liczbę.
+func (r *swiftRepository) DeleteBranches(ctx context.Context, hqCode string) (_ int, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        UPDATE swift.swift_codes
+        SET ` + markDeleted + `
+        WHERE headquarter_swift_code = $1 AND deleted_at IS NULL
+    `
+	res, err := r.q.ExecContext(ctx, query, hqCode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete branches: %w", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+// DetachBranches odłącza oddziały od centrali (headquarter_swift_code = NULL).
+// Zostaną dołączone ponownie, jeśli centrala o tym kodzie zostanie znów dodana.
+func (r *swiftRepository) DetachBranches(ctx context.Context, hqCode string) (_ int, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        UPDATE swift.swift_codes
+        SET headquarter_swift_code = NULL
         WHERE headquarter_swift_code = $1
     `
+	res, err := r.q.ExecContext(ctx, query, hqCode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to detach branches: %w", err)
+	}
 
-	rows, err := r.db.Query(query, hqCode)
+	detached, err := res.RowsAffected()
 	if err != nil {
-		return nil, fmt.Errorf("failed to query branches: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(detached), nil
+}
+
+// Wyszukiwanie łączy pełnotekstowe dopasowanie słów (dowolne ze słów zapytania)
+// z podobieństwem trigramowym, dzięki czemu znajduje też nazwy z literówkami.
+const (
+	searchDocument = `to_tsvector('simple', bank_name || ' ' || address || ' ' || town_name)`
+	searchTSQuery  = `replace(plainto_tsquery('simple', $1)::text, '&', '|')::tsquery`
+	searchWhere    = `(` + searchDocument + ` @@ ` + searchTSQuery + `
+            OR $1 <% bank_name
+            OR $1 <% address)
+          AND ($2 = '' OR country_iso2 = $2)
+          AND deleted_at IS NULL`
+)
+
+func (r *swiftRepository) Search(ctx context.Context, q SearchQuery) (_ []SearchResult, _ int, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	countQuery := `
+        SELECT COUNT(*)
+        FROM swift.swift_codes
+        WHERE ` + searchWhere
+
+	var total int
+	if err := r.q.QueryRowContext(ctx, countQuery, q.Text, q.CountryISO2).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	query := `
+        SELECT ` + swiftCodeColumns + `,
+            ts_rank(` + searchDocument + `, ` + searchTSQuery + `)
+                + GREATEST(word_similarity($1, bank_name), word_similarity($1, address), word_similarity($1, town_name)) AS score
+        FROM swift.swift_codes
+        WHERE ` + searchWhere + `
+        ORDER BY score DESC, swift_code
+        LIMIT $3 OFFSET $4
+    `
+	rows, err := r.q.QueryContext(ctx, query, q.Text, q.CountryISO2, q.Limit, q.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search swift codes: %w", err)
 	}
 	defer rows.Close()
 
-	var branches []SwiftCode
+	var results []SearchResult
 	for rows.Next() {
-		var swift SwiftCode
-		err := rows.Scan(
-			&swift.ID,
-			&swift.SwiftCode,
-			&swift.BankName,
-			&swift.Address,
-			&swift.CountryISO2,
-			&swift.CountryName,
-			&swift.IsHeadquarter,
-			&swift.HeadquarterSwiftCode,
-		)
+		var score float64
+		swift, err := scanSwiftCode(rows, &score)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan branch: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan search result: %w", err)
 		}
 
-		branches = append(branches, swift)
+		results = append(results, SearchResult{SwiftCode: swift, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate search results: %w", err)
 	}
 
-	return branches, nil
+	return results, total, nil
 }
 
-func (r *swiftRepository) CreateSwiftCode(swift SwiftCode) error {
-	existing, err := r.GetBySwiftCode(swift.SwiftCode)
+// GetAll czyta cały katalog na potrzeby porównania z importowanym plikiem; tak
+// jak import nie podlega limitowi czasu pojedynczego zapytania.
+func (r *swiftRepository) GetAll(ctx context.Context) (_ []SwiftCode, err error) {
+	defer func() { err = contextError(ctx, err) }()
+
+	query := `
+        SELECT ` + swiftCodeColumns + `
+        FROM swift.swift_codes
+        WHERE ` + notDeleted(ctx) + `
+        ORDER BY swift_code
+    `
+
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to check existing swift code: %w", err)
+		return nil, fmt.Errorf("failed to query swift codes: %w", err)
 	}
+	defer rows.Close()
 
-	if existing == nil {
-		insertQuery := `
-            INSERT INTO swift.swift_codes
-            (swift_code, bank_name, address, country_iso2, country_name, is_headquarter, headquarter_swift_code)
-            VALUES ($1, $2, $3, $4, $5, $6, $7)
-        `
-		_, err := r.db.Exec(insertQuery,
-			swift.SwiftCode,
-			swift.BankName,
-			swift.Address,
-			swift.CountryISO2,
-			swift.CountryName,
-			swift.IsHeadquarter,
-			swift.HeadquarterSwiftCode,
-		)
+	return scanSwiftCodes(rows)
+}
+
+// StreamSwiftCodes przekazuje kolejne kody do fn, czytając je partiami przez
+// kursor w transakcji tylko do odczytu, więc cały zbiór nigdy nie trafia do pamięci.
+// Limit czasu zapytania dotyczy każdej partii osobno, a nie całego eksportu.
+// Błąd zwrócony przez fn przerywa eksport.
+func (r *swiftRepository) StreamSwiftCodes(ctx context.Context, q ExportQuery, fn func(SwiftCode) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return contextError(ctx, fmt.Errorf("failed to begin export transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	conditions := []string{notDeleted(ctx)}
+	var args []interface{}
+	if q.CountryISO2 != "" {
+		args = append(args, q.CountryISO2)
+		conditions = append(conditions, fmt.Sprintf("country_iso2 = $%d", len(args)))
+	}
+	if q.IsHeadquarter != nil {
+		args = append(args, *q.IsHeadquarter)
+		conditions = append(conditions, fmt.Sprintf("is_headquarter = $%d", len(args)))
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	declareQuery := `
+        DECLARE swift_codes_export NO SCROLL CURSOR FOR
+        SELECT ` + swiftCodeColumns + `
+        FROM swift.swift_codes
+        ` + where + `
+        ORDER BY swift_code
+    `
+	if _, err := tx.ExecContext(ctx, declareQuery, args...); err != nil {
+		return contextError(ctx, fmt.Errorf("failed to declare export cursor: %w", err))
+	}
+
+	txRepo := &swiftRepository{db: r.db, q: tx, queryTimeout: r.queryTimeout}
+	for {
+		batch, err := txRepo.fetchExportBatch(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to insert swift code: %w", err)
+			return err
+		}
+
+		for _, swift := range batch {
+			if err := fn(swift); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			break
 		}
-		log.Printf("[Upsert] Inserted new swift_code=%s", swift.SwiftCode)
-		return nil
 	}
 
-	logDifferences(*existing, swift)
+	return contextError(ctx, tx.Commit())
+}
 
-	updateQuery := `
-        UPDATE swift.swift_codes
-        SET
-            bank_name = $2,
-            address = $3,
-            country_iso2 = $4,
-            country_name = $5,
-            is_headquarter = $6,
-            headquarter_swift_code = $7
-        WHERE swift_code = $1
+func (r *swiftRepository) fetchExportBatch(ctx context.Context) (_ []SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	rows, err := r.q.QueryContext(ctx, fmt.Sprintf(`FETCH FORWARD %d FROM swift_codes_export`, exportBatchSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch swift codes: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSwiftCodes(rows)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+const insertSwiftCodeQuery = `
+        INSERT INTO swift.swift_codes
+        (swift_code, bank_name, address, town_name, country_iso2, country_name, is_headquarter, headquarter_swift_code, code_type, time_zone,
+         branch_information, zip_code, institution_type)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
     `
-	_, err = r.db.Exec(updateQuery,
+
+// swiftCodeValues to parametry $1-$13 zapytań insertSwiftCodeQuery i UPDATE w UpsertSwiftCode.
+func swiftCodeValues(swift SwiftCode) []interface{} {
+	return []interface{}{
 		swift.SwiftCode,
 		swift.BankName,
 		swift.Address,
+		swift.TownName,
 		swift.CountryISO2,
 		swift.CountryName,
 		swift.IsHeadquarter,
 		swift.HeadquarterSwiftCode,
-	)
+		swift.CodeType,
+		swift.TimeZone,
+		swift.BranchInformation,
+		swift.ZipCode,
+		swift.InstitutionType,
+	}
+}
+
+// CreateSwiftCode dodaje nowy kod. Usunięty rekord o tym kodzie nie blokuje
+// dodania - zostaje nadpisany i przywrócony; ErrDuplicateSwiftCode dotyczy tylko
+// aktywnego rekordu.
+func (r *swiftRepository) CreateSwiftCode(ctx context.Context, swift SwiftCode) (err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := insertSwiftCodeQuery + `
+        ON CONFLICT (swift_code) DO UPDATE
+        SET
+            bank_name = EXCLUDED.bank_name,
+            address = EXCLUDED.address,
+            town_name = EXCLUDED.town_name,
+            country_iso2 = EXCLUDED.country_iso2,
+            country_name = EXCLUDED.country_name,
+            is_headquarter = EXCLUDED.is_headquarter,
+            headquarter_swift_code = EXCLUDED.headquarter_swift_code,
+            code_type = EXCLUDED.code_type,
+            time_zone = EXCLUDED.time_zone,
+            branch_information = EXCLUDED.branch_information,
+            zip_code = EXCLUDED.zip_code,
+            institution_type = EXCLUDED.institution_type,
+            deleted_at = NULL,
+            deleted_by = NULL
+        WHERE swift_codes.deleted_at IS NOT NULL
+        RETURNING (xmax <> 0) AS revived
+    `
+	var revived bool
+	err = r.q.QueryRowContext(ctx, query, swiftCodeValues(swift)...).Scan(&revived)
 	if err != nil {
-		return fmt.Errorf("failed to update swift code: %w", err)
+		if err == sql.ErrNoRows || isUniqueViolation(err) {
+			return ErrDuplicateSwiftCode
+		}
+		return fmt.Errorf("failed to insert swift code: %w", err)
+	}
+	if revived {
+		log.Printf("[Create] Restored deleted swift_code=%s", swift.SwiftCode)
+	}
+
+	return nil
+}
+
+func (r *swiftRepository) UpsertSwiftCode(ctx context.Context, swift SwiftCode) (_ bool, _ []FieldChange, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	// Usunięty rekord nie blokuje zapisu - nadpisanie go przywraca.
+	existing, err := r.GetBySwiftCodeForUpdate(WithDeleted(ctx), swift.SwiftCode)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Oddział bez centrali w bazie jest zapisywany bez headquarter_swift_code
+	// (pilnuje tego trigger), więc porównujemy z tym, co faktycznie trafi do tabeli.
+	if swift.HeadquarterSwiftCode.Valid {
+		hq, err := r.GetBySwiftCode(ctx, swift.HeadquarterSwiftCode.String)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to check headquarter swift code: %w", err)
+		}
+		if hq == nil {
+			swift.HeadquarterSwiftCode = sql.NullString{}
+		}
+	}
+
+	if existing == nil {
+		var inserted string
+		err := r.q.QueryRowContext(ctx, insertSwiftCodeQuery+` ON CONFLICT (swift_code) DO NOTHING RETURNING swift_code`,
+			swiftCodeValues(swift)...).Scan(&inserted)
+		if err == nil {
+			log.Printf("[Upsert] Inserted new swift_code=%s", swift.SwiftCode)
+			return true, nil, nil
+		}
+		if err != sql.ErrNoRows {
+			return false, nil, fmt.Errorf("failed to insert swift code: %w", err)
+		}
+
+		// Równoległe żądanie wstawiło ten kod po naszym odczycie. ON CONFLICT czekał
+		// na jego commit, więc rekord jest już widoczny - nadpisujemy go jak istniejący.
+		existing, err = r.GetBySwiftCodeForUpdate(WithDeleted(ctx), swift.SwiftCode)
+		if err != nil {
+			return false, nil, err
+		}
+		if existing == nil {
+			return false, nil, fmt.Errorf("swift code %s disappeared during upsert", swift.SwiftCode)
+		}
+	}
+
+	// Przywrócony rekord jest dla klienta nowy, więc nie zwracamy zmian względem usuniętego.
+	revived := existing.DeletedAt.Valid
+	var changes []FieldChange
+	if !revived {
+		changes = DiffSwiftCodes(*existing, swift)
+	}
+	for _, change := range changes {
+		log.Printf("[Upsert] SwiftCode=%s: %s changed from %v to %v",
+			existing.SwiftCode, change.Field, change.OldValue, change.NewValue)
+	}
+
+	updateQuery := `
+        UPDATE swift.swift_codes
+        SET
+            bank_name = $2,
+            address = $3,
+            town_name = $4,
+            country_iso2 = $5,
+            country_name = $6,
+            is_headquarter = $7,
+            headquarter_swift_code = $8,
+            code_type = $9,
+            time_zone = $10,
+            branch_information = $11,
+            zip_code = $12,
+            institution_type = $13,
+            deleted_at = NULL,
+            deleted_by = NULL
+        WHERE swift_code = $1
+    `
+	_, err = r.q.ExecContext(ctx, updateQuery, swiftCodeValues(swift)...)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to update swift code: %w", err)
+	}
+
+	if revived {
+		log.Printf("[Upsert] Restored deleted swift_code=%s", swift.SwiftCode)
+		return true, nil, nil
 	}
 
 	log.Printf("[Upsert] Updated existing swift_code=%s", swift.SwiftCode)
+	return false, changes, nil
+}
+
+// GetBySwiftCodeForUpdate działa jak GetBySwiftCode, ale blokuje rekord do końca
+// transakcji, żeby zmiany liczone z odczytanej wersji nie nadpisały równoległego zapisu.
+func (r *swiftRepository) GetBySwiftCodeForUpdate(ctx context.Context, code string) (_ *SwiftCode, err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	query := `
+        SELECT ` + swiftCodeColumns + `
+        FROM swift.swift_codes
+        WHERE swift_code = $1 AND ` + notDeleted(ctx) + `
+        FOR UPDATE
+    `
+	swift, err := scanSwiftCode(r.q.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock swift code: %w", err)
+	}
+	return &swift, nil
+}
+
+func (r *swiftRepository) UpdateSwiftCode(ctx context.Context, code string, update SwiftCodeUpdate) (err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
+	var sets []string
+	args := []interface{}{code}
+	addSet := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if update.BankName != nil {
+		addSet("bank_name", *update.BankName)
+	}
+	if update.Address != nil {
+		addSet("address", *update.Address)
+	}
+	if update.TownName != nil {
+		addSet("town_name", *update.TownName)
+	}
+	if update.CountryISO2 != nil {
+		addSet("country_iso2", *update.CountryISO2)
+	}
+	if update.CountryName != nil {
+		addSet("country_name", *update.CountryName)
+	}
+	if update.IsHeadquarter != nil {
+		addSet("is_headquarter", *update.IsHeadquarter)
+	}
+	if update.HeadquarterSwiftCode != nil {
+		addSet("headquarter_swift_code", *update.HeadquarterSwiftCode)
+	}
+	if update.CodeType != nil {
+		addSet("code_type", *update.CodeType)
+	}
+	if update.TimeZone != nil {
+		addSet("time_zone", *update.TimeZone)
+	}
+	if update.BranchInformation != nil {
+		addSet("branch_information", *update.BranchInformation)
+	}
+	if update.ZipCode != nil {
+		addSet("zip_code", *update.ZipCode)
+	}
+	if update.InstitutionType != nil {
+		addSet("institution_type", *update.InstitutionType)
+	}
+
+	if len(sets) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+        UPDATE swift.swift_codes
+        SET %s
+        WHERE swift_code = $1 AND deleted_at IS NULL
+    `, strings.Join(sets, ", "))
+	res, err := r.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update swift code: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
 	return nil
 }
 
-func logDifferences(existing SwiftCode, updated SwiftCode) {
+// DiffSwiftCodes zwraca listę pól, których wartość różni się między
+// istniejącym a nowym rekordem (nazwy pól jak w JSON).
+func DiffSwiftCodes(existing SwiftCode, updated SwiftCode) []FieldChange {
+	var changes []FieldChange
 	if existing.BankName != updated.BankName {
-		log.Printf("[Upsert] SwiftCode=%s: BankName changed from %q to %q",
-			existing.SwiftCode, existing.BankName, updated.BankName)
+		changes = append(changes, FieldChange{"bankName", existing.BankName, updated.BankName})
 	}
 	if existing.Address != updated.Address {
-		log.Printf("[Upsert] SwiftCode=%s: Address changed from %q to %q",
-			existing.SwiftCode, existing.Address, updated.Address)
+		changes = append(changes, FieldChange{"address", existing.Address, updated.Address})
+	}
+	if existing.TownName != updated.TownName {
+		changes = append(changes, FieldChange{"townName", existing.TownName, updated.TownName})
 	}
 	if existing.CountryISO2 != updated.CountryISO2 {
-		log.Printf("[Upsert] SwiftCode=%s: CountryISO2 changed from %q to %q",
-			existing.SwiftCode, existing.CountryISO2, updated.CountryISO2)
+		changes = append(changes, FieldChange{"countryISO2", existing.CountryISO2, updated.CountryISO2})
 	}
 	if existing.CountryName != updated.CountryName {
-		log.Printf("[Upsert] SwiftCode=%s: CountryName changed from %q to %q",
-			existing.SwiftCode, existing.CountryName, updated.CountryName)
+		changes = append(changes, FieldChange{"countryName", existing.CountryName, updated.CountryName})
 	}
 	if existing.IsHeadquarter != updated.IsHeadquarter {
-		log.Printf("[Upsert] SwiftCode=%s: IsHeadquarter changed from %v to %v",
-			existing.SwiftCode, existing.IsHeadquarter, updated.IsHeadquarter)
+		changes = append(changes, FieldChange{"isHeadquarter", existing.IsHeadquarter, updated.IsHeadquarter})
 	}
 
 	oldHQ := ""
@@ -222,17 +992,44 @@ func logDifferences(existing SwiftCode, updated SwiftCode) {
 		newHQ = updated.HeadquarterSwiftCode.String
 	}
 	if oldHQ != newHQ {
-		log.Printf("[Upsert] SwiftCode=%s: HeadquarterSwiftCode changed from %q to %q",
-			existing.SwiftCode, oldHQ, newHQ)
+		changes = append(changes, FieldChange{"headquarterSwiftCode", oldHQ, newHQ})
+	}
+	if existing.CodeType != updated.CodeType {
+		changes = append(changes, FieldChange{"codeType", existing.CodeType, updated.CodeType})
+	}
+	if existing.TimeZone != updated.TimeZone {
+		changes = append(changes, FieldChange{"timeZone", existing.TimeZone, updated.TimeZone})
+	}
+	if existing.BranchInformation != updated.BranchInformation {
+		changes = append(changes, FieldChange{"branchInformation", existing.BranchInformation, updated.BranchInformation})
+	}
+	if existing.ZipCode != updated.ZipCode {
+		changes = append(changes, FieldChange{"zipCode", existing.ZipCode, updated.ZipCode})
+	}
+	if existing.InstitutionType != updated.InstitutionType {
+		changes = append(changes, FieldChange{"institutionType", existing.InstitutionType, updated.InstitutionType})
 	}
+
+	return changes
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
 }
 
-func (r *swiftRepository) DeleteBySwiftCode(code string) error {
+// DeleteBySwiftCode oznacza rekord jako usunięty; RestoreSwiftCode go przywraca,
+// a fizycznie usuwa go dopiero PurgeDeleted.
+func (r *swiftRepository) DeleteBySwiftCode(ctx context.Context, code string) (err error) {
+	ctx, done := r.queryContext(ctx, &err)
+	defer done()
+
 	query := `
-        DELETE FROM swift.swift_codes
-        WHERE swift_code = $1
+        UPDATE swift.swift_codes
+        SET ` + markDeleted + `
+        WHERE swift_code = $1 AND deleted_at IS NULL
     `
-	res, err := r.db.Exec(query, code)
+	res, err := r.q.ExecContext(ctx, query, code)
 	if err != nil {
 		return fmt.Errorf("failed to delete swift code: %w", err)
 	}
@@ -248,3 +1045,267 @@ func (r *swiftRepository) DeleteBySwiftCode(code string) error {
 
 	return nil
 }
+
+// ImportSwiftCodes ładuje wszystkie rekordy w jednej transakcji: COPY do tabeli
+// tymczasowej, a następnie jeden INSERT ... ON CONFLICT. Błąd na dowolnym etapie
+// wycofuje cały import. Import przerywa tylko ctx - limit czasu pojedynczego
+// zapytania go nie obejmuje, bo ładowanie całego katalogu trwa dłużej.
+func (r *swiftRepository) ImportSwiftCodes(ctx context.Context, codes []SwiftCode, opts ImportOptions) (_ ImportResult, err error) {
+	defer func() { err = contextError(ctx, err) }()
+
+	var result ImportResult
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin import transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setAudit(ctx, tx); err != nil {
+		return result, err
+	}
+
+	if err := copyToStaging(ctx, tx, codes); err != nil {
+		return result, err
+	}
+
+	if err := detachMissingHeadquarters(ctx, tx); err != nil {
+		return result, err
+	}
+
+	if opts.DeleteMissing || len(opts.DeleteCodes) > 0 {
+		if err := checkDeleteThreshold(ctx, tx, opts); err != nil {
+			return result, err
+		}
+	}
+
+	upsertQuery := `
+        WITH revived AS (
+            SELECT swift_code
+            FROM swift.swift_codes
+            WHERE deleted_at IS NOT NULL
+              AND swift_code IN (SELECT swift_code FROM swift_codes_import)
+        ),
+        upserted AS (
+            INSERT INTO swift.swift_codes
+            (swift_code, bank_name, address, town_name, country_iso2, country_name, is_headquarter, headquarter_swift_code, code_type, time_zone,
+             branch_information, zip_code, institution_type)
+            SELECT swift_code, bank_name, address, town_name, country_iso2, country_name, is_headquarter, headquarter_swift_code, code_type, time_zone,
+                   branch_information, zip_code, institution_type
+            FROM swift_codes_import
+            ON CONFLICT (swift_code) DO UPDATE
+            SET
+                bank_name = EXCLUDED.bank_name,
+                address = EXCLUDED.address,
+                town_name = EXCLUDED.town_name,
+                country_iso2 = EXCLUDED.country_iso2,
+                country_name = EXCLUDED.country_name,
+                is_headquarter = EXCLUDED.is_headquarter,
+                headquarter_swift_code = EXCLUDED.headquarter_swift_code,
+                code_type = EXCLUDED.code_type,
+                time_zone = EXCLUDED.time_zone,
+                branch_information = EXCLUDED.branch_information,
+                zip_code = EXCLUDED.zip_code,
+                institution_type = EXCLUDED.institution_type,
+                deleted_at = NULL,
+                deleted_by = NULL
+            WHERE swift_codes.deleted_at IS NOT NULL
+               OR (swift_codes.bank_name, swift_codes.address, swift_codes.town_name, swift_codes.country_iso2,
+                   swift_codes.country_name, swift_codes.is_headquarter, swift_codes.headquarter_swift_code,
+                   swift_codes.code_type, swift_codes.time_zone, swift_codes.branch_information,
+                   swift_codes.zip_code, swift_codes.institution_type)
+                IS DISTINCT FROM
+                  (EXCLUDED.bank_name, EXCLUDED.address, EXCLUDED.town_name, EXCLUDED.country_iso2,
+                   EXCLUDED.country_name, EXCLUDED.is_headquarter, EXCLUDED.headquarter_swift_code,
+                   EXCLUDED.code_type, EXCLUDED.time_zone, EXCLUDED.branch_information,
+                   EXCLUDED.zip_code, EXCLUDED.institution_type)
+            -- Przywrócony rekord liczymy jako dodany, tak jak w DiffImport.
+            RETURNING (xmax = 0 OR swift_code IN (SELECT swift_code FROM revived)) AS inserted
+        )
+        SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted)
+        FROM upserted
+    `
+	if err := tx.QueryRowContext(ctx, upsertQuery).Scan(&result.Inserted, &result.Updated); err != nil {
+		return ImportResult{}, fmt.Errorf("failed to upsert imported swift codes: %w", err)
+	}
+	result.Unchanged = len(codes) - result.Inserted - result.Updated
+
+	if len(opts.DeleteCodes) > 0 {
+		deleteQuery := `
+            UPDATE swift.swift_codes
+            SET ` + markDeleted + `
+            WHERE swift_code = ANY($1) AND deleted_at IS NULL
+        `
+		res, err := tx.ExecContext(ctx, deleteQuery, pq.Array(opts.DeleteCodes))
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("failed to delete swift codes flagged in import: %w", err)
+		}
+		deleted, err := res.RowsAffected()
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		result.Deleted += int(deleted)
+	}
+
+	if opts.DeleteMissing {
+		deleteQuery := `
+            UPDATE swift.swift_codes s
+            SET ` + markDeleted + `
+            WHERE deleted_at IS NULL
+              AND NOT EXISTS (SELECT 1 FROM swift_codes_import i WHERE i.swift_code = s.swift_code)
+        `
+		res, err := tx.ExecContext(ctx, deleteQuery)
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("failed to delete swift codes missing from import: %w", err)
+		}
+		deleted, err := res.RowsAffected()
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		result.Deleted += int(deleted)
+	}
+
+	if result.Deleted > 0 {
+		if result.Detached, err = detachBranchesOfDeleted(ctx, tx); err != nil {
+			return ImportResult{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ImportResult{}, fmt.Errorf("failed to commit import transaction: %w", err)
+	}
+
+	log.Printf("[Import] swift_codes: %d inserted, %d updated, %d unchanged, %d deleted, %d branches detached",
+		result.Inserted, result.Updated, result.Unchanged, result.Deleted, result.Detached)
+	return result, nil
+}
+
+// detachMissingHeadquarters czyści w tabeli tymczasowej kod centrali oddziałów,
+// których centrali nie ma ani w pliku, ani w bazie - tak jak zrobiłby to trigger
+// przy zapisie. Dzięki temu ponowny import tego samego pliku nie liczy ich jako zmienionych.
+func detachMissingHeadquarters(ctx context.Context, tx *sql.Tx) error {
+	query := `
+        UPDATE swift_codes_import i
+        SET headquarter_swift_code = NULL
+        WHERE headquarter_swift_code IS NOT NULL
+          AND NOT EXISTS (SELECT 1 FROM swift_codes_import h WHERE h.swift_code = i.headquarter_swift_code)
+          AND NOT EXISTS (
+              SELECT 1 FROM swift.swift_codes h
+              WHERE h.swift_code = i.headquarter_swift_code AND h.deleted_at IS NULL
+          )
+    `
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to detach branches without headquarters: %w", err)
+	}
+	return nil
+}
+
+// checkDeleteThreshold chroni przed plikiem, który usunąłby większość katalogu -
+// niepełnym plikiem w trybie sync albo zbyt wieloma wierszami z flagą D.
+func checkDeleteThreshold(ctx context.Context, tx *sql.Tx, opts ImportOptions) error {
+	query := `
+        SELECT
+            COUNT(*),
+            COUNT(*) FILTER (
+                WHERE swift_code = ANY($2)
+                   OR ($1 AND NOT EXISTS (SELECT 1 FROM swift_codes_import i WHERE i.swift_code = s.swift_code))
+            )
+        FROM swift.swift_codes s
+        WHERE deleted_at IS NULL
+    `
+	var total, deleted int
+	if err := tx.QueryRowContext(ctx, query, opts.DeleteMissing, pq.Array(opts.DeleteCodes)).Scan(&total, &deleted); err != nil {
+		return fmt.Errorf("failed to count swift codes deleted by import: %w", err)
+	}
+
+	if total > 0 && float64(deleted)*100/float64(total) > opts.MaxDeletePercent {
+		return fmt.Errorf("%w: import would delete %d of %d swift codes (limit %.1f%%)",
+			ErrDeleteThresholdExceeded, deleted, total, opts.MaxDeletePercent)
+	}
+
+	return nil
+}
+
+// detachBranchesOfDeleted odłącza aktywne oddziały od central usuniętych przez
+// import - plik nie wybiera polityki, więc działa jak cascade=detach w API.
+// Centrala przywrócona później dołączy je z powrotem.
+func detachBranchesOfDeleted(ctx context.Context, tx *sql.Tx) (int, error) {
+	query := `
+        UPDATE swift.swift_codes b
+        SET headquarter_swift_code = NULL
+        FROM swift.swift_codes h
+        WHERE h.swift_code = b.headquarter_swift_code
+          AND h.deleted_at IS NOT NULL
+          AND b.deleted_at IS NULL
+    `
+	res, err := tx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to detach branches of deleted headquarters: %w", err)
+	}
+
+	detached, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(detached), nil
+}
+
+func copyToStaging(ctx context.Context, tx *sql.Tx, codes []SwiftCode) error {
+	createQuery := `
+        CREATE TEMP TABLE swift_codes_import (
+            swift_code VARCHAR(11) PRIMARY KEY,
+            bank_name VARCHAR(255) NOT NULL,
+            address TEXT NOT NULL,
+            town_name VARCHAR(100) NOT NULL,
+            country_iso2 CHAR(2) NOT NULL,
+            country_name VARCHAR(100) NOT NULL,
+            is_headquarter BOOLEAN NOT NULL,
+            headquarter_swift_code VARCHAR(11),
+            code_type VARCHAR(10) NOT NULL,
+            time_zone VARCHAR(64) NOT NULL,
+            branch_information VARCHAR(255) NOT NULL,
+            zip_code VARCHAR(20) NOT NULL,
+            institution_type VARCHAR(10) NOT NULL
+        ) ON COMMIT DROP
+    `
+	if _, err := tx.ExecContext(ctx, createQuery); err != nil {
+		return fmt.Errorf("failed to create import staging table: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("swift_codes_import",
+		"swift_code", "bank_name", "address", "town_name", "country_iso2", "country_name",
+		"is_headquarter", "headquarter_swift_code", "code_type", "time_zone",
+		"branch_information", "zip_code", "institution_type"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare import copy: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, swift := range codes {
+		_, err := stmt.ExecContext(ctx,
+			swift.SwiftCode,
+			swift.BankName,
+			swift.Address,
+			swift.TownName,
+			swift.CountryISO2,
+			swift.CountryName,
+			swift.IsHeadquarter,
+			swift.HeadquarterSwiftCode,
+			swift.CodeType,
+			swift.TimeZone,
+			swift.BranchInformation,
+			swift.ZipCode,
+			swift.InstitutionType,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy swift code %s: %w", swift.SwiftCode, err)
+		}
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush import copy: %w", err)
+	}
+
+	return nil
+}
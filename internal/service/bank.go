@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BankResponse to wszystkie kody jednej instytucji (te same pierwsze 4 znaki BIC),
+// pogrupowane według kraju i kodu lokalizacji.
+type BankResponse struct {
+	InstitutionCode  string        `json:"institutionCode"`
+	BankNames        []string      `json:"bankNames"`
+	TotalCount       int           `json:"totalCount"`
+	HeadquarterCount int           `json:"headquarterCount"`
+	BranchCount      int           `json:"branchCount"`
+	Countries        []BankCountry `json:"countries"`
+}
+
+type BankCountry struct {
+	CountryISO2      string         `json:"countryISO2"`
+	CountryName      string         `json:"countryName"`
+	HeadquarterCount int            `json:"headquarterCount"`
+	BranchCount      int            `json:"branchCount"`
+	Locations        []BankLocation `json:"locations"`
+}
+
+// BankLocation odpowiada jednemu BIC8: centrali (o ile istnieje) i jej oddziałom.
+type BankLocation struct {
+	LocationCode string           `json:"locationCode"`
+	BIC8         string           `json:"bic8"`
+	Headquarter  *SwiftCodeBasic  `json:"headquarter"`
+	Branches     []SwiftCodeBasic `json:"branches"`
+	BranchCount  int              `json:"branchCount"`
+}
+
+// GetBank zwraca drzewo kodów instytucji. W odróżnieniu od widoku centrali
+// obejmuje wszystkie BIC8 banku, także z innych krajów i lokalizacji.
+func (s *swiftService) GetBank(ctx context.Context, institutionCode string) (*BankResponse, error) {
+	institutionCode = strings.ToUpper(strings.TrimSpace(institutionCode))
+	if len(institutionCode) != 4 || !isAlpha(institutionCode) {
+		verr := &ValidationError{}
+		verr.add("institutionCode", "must be 4 letters")
+		return nil, verr
+	}
+
+	swiftCodes, err := s.repo.GetByInstitutionCode(ctx, institutionCode)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting swift codes by institution: %w", err)
+	}
+	if len(swiftCodes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBankNotFound, institutionCode)
+	}
+
+	bank := &BankResponse{InstitutionCode: institutionCode, Countries: []BankCountry{}}
+	names := make(map[string]bool)
+
+	// Kody są posortowane, więc kraj i lokalizacja zmieniają się tylko na granicach grup.
+	for _, sc := range swiftCodes {
+		countryISO2, locationCode := sc.SwiftCode[4:6], sc.SwiftCode[6:8]
+
+		if n := len(bank.Countries); n == 0 || bank.Countries[n-1].CountryISO2 != countryISO2 {
+			bank.Countries = append(bank.Countries, BankCountry{
+				CountryISO2: countryISO2,
+				CountryName: sc.CountryName,
+				Locations:   []BankLocation{},
+			})
+		}
+		country := &bank.Countries[len(bank.Countries)-1]
+
+		if n := len(country.Locations); n == 0 || country.Locations[n-1].LocationCode != locationCode {
+			country.Locations = append(country.Locations, BankLocation{
+				LocationCode: locationCode,
+				BIC8:         sc.SwiftCode[:8],
+				Branches:     []SwiftCodeBasic{},
+			})
+		}
+		location := &country.Locations[len(country.Locations)-1]
+
+		basic := toSwiftCodeBasic(sc)
+		if sc.IsHeadquarter {
+			location.Headquarter = &basic
+			country.HeadquarterCount++
+			bank.HeadquarterCount++
+		} else {
+			location.Branches = append(location.Branches, basic)
+			location.BranchCount++
+			country.BranchCount++
+			bank.BranchCount++
+		}
+
+		names[sc.BankName] = true
+	}
+
+	bank.TotalCount = len(swiftCodes)
+	for name := range names {
+		bank.BankNames = append(bank.BankNames, name)
+	}
+	sort.Strings(bank.BankNames)
+
+	return bank, nil
+}
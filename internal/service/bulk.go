@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"swift-codes-api/internal/repository"
+)
+
+// MaxBulkItems ogranicza liczbę pozycji w jednym żądaniu zbiorczym.
+const MaxBulkItems = 1000
+
+type BulkMode string
+
+const (
+	// BulkModeAtomic wykonuje wszystkie pozycje w jednej transakcji - albo wszystkie, albo żadna.
+	BulkModeAtomic BulkMode = "atomic"
+	// BulkModeBestEffort wykonuje każdą pozycję w osobnej transakcji i zapisuje te, które się udały.
+	BulkModeBestEffort BulkMode = "bestEffort"
+)
+
+func ParseBulkMode(mode string) (BulkMode, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", strings.ToLower(string(BulkModeAtomic)):
+		return BulkModeAtomic, nil
+	case strings.ToLower(string(BulkModeBestEffort)):
+		return BulkModeBestEffort, nil
+	default:
+		return "", fmt.Errorf("unknown bulk mode %q, expected %q or %q", mode, BulkModeAtomic, BulkModeBestEffort)
+	}
+}
+
+// BulkItemResult to wynik jednej pozycji; Err == nil oznacza sukces.
+type BulkItemResult struct {
+	Index     int
+	SwiftCode string
+	Err       error
+}
+
+// BulkCreateSwiftCodes tworzy wiele kodów z tymi samymi regułami co CreateSwiftCode.
+func (s *swiftService) BulkCreateSwiftCodes(ctx context.Context, inputs []CreateSwiftCodeInput, mode BulkMode) ([]BulkItemResult, error) {
+	if err := validateBulkSize(len(inputs)); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkItemResult, len(inputs))
+	codes := make([]repository.SwiftCode, len(inputs))
+	for i, input := range inputs {
+		results[i] = BulkItemResult{Index: i, SwiftCode: NormalizeBIC(input.SwiftCode)}
+		codes[i], results[i].Err = prepareSwiftCode(input)
+	}
+	markBulkDuplicates(results)
+
+	return s.runBulk(ctx, results, mode, func(repo repository.SwiftRepository, i int) error {
+		return createSwiftCode(ctx, repo, codes[i])
+	})
+}
+
+// BulkDeleteSwiftCodes usuwa wiele kodów z tymi samymi regułami co DeleteSwiftCode;
+// policy dotyczy każdej usuwanej centrali.
+func (s *swiftService) BulkDeleteSwiftCodes(ctx context.Context, codes []string, mode BulkMode, policy BranchPolicy) ([]BulkItemResult, error) {
+	if err := validateBulkSize(len(codes)); err != nil {
+		return nil, err
+	}
+	policy, err := validateBranchPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkItemResult, len(codes))
+	for i, code := range codes {
+		results[i] = BulkItemResult{Index: i, SwiftCode: NormalizeBIC(code)}
+		if fields := ValidateBIC(results[i].SwiftCode, ""); len(fields) > 0 {
+			results[i].Err = &ValidationError{Fields: fields}
+		}
+	}
+	markBulkDuplicates(results)
+
+	return s.runBulk(ctx, results, mode, func(repo repository.SwiftRepository, i int) error {
+		_, err := deleteSwiftCode(ctx, repo, results[i].SwiftCode, policy)
+		return err
+	})
+}
+
+// runBulk wykonuje apply dla poprawnych pozycji. W trybie atomowym pierwsza
+// nieudana pozycja (także błąd walidacji) wycofuje całą transakcję.
+func (s *swiftService) runBulk(ctx context.Context, results []BulkItemResult, mode BulkMode, apply func(repo repository.SwiftRepository, i int) error) ([]BulkItemResult, error) {
+	mode, err := ParseBulkMode(string(mode))
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == BulkModeBestEffort {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+					return apply(repo, i)
+				})
+			}
+		}
+		return results, nil
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			markBulkRolledBack(results)
+			return results, nil
+		}
+	}
+
+	failed := false
+	err = s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		for i := range results {
+			if err := apply(repo, i); err != nil {
+				results[i].Err = err
+				failed = true
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if !failed {
+			return nil, fmt.Errorf("service error applying bulk operation: %w", err)
+		}
+		markBulkRolledBack(results)
+	}
+
+	return results, nil
+}
+
+func validateBulkSize(n int) error {
+	verr := &ValidationError{}
+	if n == 0 {
+		verr.add("items", "must contain at least one item")
+	} else if n > MaxBulkItems {
+		verr.add("items", "must contain at most %d items, got %d", MaxBulkItems, n)
+	}
+	return verr.errOrNil()
+}
+
+// markBulkDuplicates odrzuca kolejne wystąpienia tego samego kodu w jednym żądaniu.
+func markBulkDuplicates(results []BulkItemResult) {
+	first := make(map[string]int, len(results))
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		if index, seen := first[results[i].SwiftCode]; seen {
+			verr := &ValidationError{}
+			verr.add("swiftCode", "duplicate of item %d", index)
+			results[i].Err = verr
+			continue
+		}
+		first[results[i].SwiftCode] = i
+	}
+}
+
+func markBulkRolledBack(results []BulkItemResult) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Err = ErrBulkRolledBack
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"swift-codes-api/internal/repository"
+)
+
+// CountryResponse to wpis słownika krajów z liczbą kodów zapisanych w bazie.
+type CountryResponse struct {
+	CountryISO2      string `json:"countryISO2"`
+	CountryISO3      string `json:"countryISO3"`
+	CountryName      string `json:"countryName"`
+	TotalCount       int    `json:"totalCount"`
+	HeadquarterCount int    `json:"headquarterCount"`
+	BranchCount      int    `json:"branchCount"`
+}
+
+type CountriesResponse struct {
+	Countries  []CountryResponse `json:"countries"`
+	TotalCount int               `json:"totalCount"`
+}
+
+// ListCountries zwraca kraje, dla których istnieją kody SWIFT; includeEmpty
+// dołącza pozostałe kraje ze słownika.
+func (s *swiftService) ListCountries(ctx context.Context, includeEmpty bool) (*CountriesResponse, error) {
+	countries, err := s.repo.ListCountries(ctx, includeEmpty)
+	if err != nil {
+		return nil, fmt.Errorf("service error listing countries: %w", err)
+	}
+
+	resp := &CountriesResponse{
+		Countries:  make([]CountryResponse, 0, len(countries)),
+		TotalCount: len(countries),
+	}
+	for _, c := range countries {
+		resp.Countries = append(resp.Countries, toCountryResponse(c))
+	}
+
+	return resp, nil
+}
+
+// GetCountry zwraca dane kraju ze słownika, także gdy nie ma dla niego żadnych kodów.
+func (s *swiftService) GetCountry(ctx context.Context, countryISO2 string) (*CountryResponse, error) {
+	countryISO2 = strings.ToUpper(strings.TrimSpace(countryISO2))
+	if verr := validateCountryISO2(countryISO2); verr != nil {
+		return nil, verr
+	}
+
+	country, err := s.repo.GetCountry(ctx, countryISO2)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting country: %w", err)
+	}
+	if country == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCountry, countryISO2)
+	}
+
+	resp := toCountryResponse(*country)
+	return &resp, nil
+}
+
+// lookupCountry sprawdza kod kraju podany jako filtr; nieznany kod to błąd
+// walidacji, a nie brak wyników.
+func (s *swiftService) lookupCountry(ctx context.Context, countryISO2 string) (*repository.Country, error) {
+	if verr := validateCountryISO2(countryISO2); verr != nil {
+		return nil, verr
+	}
+
+	country, err := s.repo.GetCountry(ctx, countryISO2)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting country: %w", err)
+	}
+	if country == nil {
+		verr := &ValidationError{}
+		verr.add("countryISO2", "unknown country code %s", countryISO2)
+		return nil, verr
+	}
+
+	return country, nil
+}
+
+func validateCountryISO2(countryISO2 string) *ValidationError {
+	if len(countryISO2) != 2 || !isAlpha(countryISO2) {
+		verr := &ValidationError{}
+		verr.add("countryISO2", "must consist of 2 letters")
+		return verr
+	}
+	return nil
+}
+
+func toCountryResponse(c repository.Country) CountryResponse {
+	return CountryResponse{
+		CountryISO2:      c.ISO2,
+		CountryISO3:      c.ISO3,
+		CountryName:      c.Name,
+		TotalCount:       c.HeadquarterCount + c.BranchCount,
+		HeadquarterCount: c.HeadquarterCount,
+		BranchCount:      c.BranchCount,
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import "errors"
+
+// Kategorie błędów serwisu. Handlery dobierają status HTTP przez errors.Is,
+// a konkretne błędy poniżej (i ValidationError) pasują do swojej kategorii.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+)
+
+var (
+	ErrSwiftCodeExists         = categorized(ErrConflict, "swift code already exists")
+	ErrSwiftCodeNotFound       = categorized(ErrNotFound, "swift code not found")
+	ErrSwiftCodeHasBranches    = categorized(ErrConflict, "swift code has branches")
+	ErrSwiftCodeNotDeleted     = categorized(ErrConflict, "swift code is not deleted")
+	ErrCountryNotFound         = categorized(ErrNotFound, "no swift codes found for country")
+	ErrUnknownCountry          = categorized(ErrNotFound, "unknown country code")
+	ErrBankNotFound            = categorized(ErrNotFound, "no swift codes found for institution")
+	ErrImportRejected          = categorized(ErrValidation, "import rejected")
+	ErrDeleteThresholdExceeded = categorized(ErrConflict, "import delete threshold exceeded")
+)
+
+// ErrBulkRolledBack oznacza pozycję, która sama była poprawna, ale została
+// wycofana (albo nie była wykonana), bo w trybie atomowym zawiodła inna pozycja.
+var ErrBulkRolledBack = errors.New("rolled back because another item failed")
+
+// categoryError to błąd z własnym komunikatem, który errors.Is dopasowuje
+// również do jego kategorii.
+type categoryError struct {
+	category error
+	message  string
+}
+
+func categorized(category error, message string) error {
+	return &categoryError{category: category, message: message}
+}
+
+func (e *categoryError) Error() string {
+	return e.message
+}
+
+func (e *categoryError) Is(target error) bool {
+	return target == e.category
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"swift-codes-api/internal/repository"
+)
+
+type HistoryResponse struct {
+	SwiftCode string                 `json:"swiftCode"`
+	History   []HistoryEntryResponse `json:"history"`
+}
+
+// HistoryEntryResponse to jedna zmiana rekordu: OldValues jest puste dla INSERT,
+// NewValues dla DELETE, a Changes wypełniamy tylko dla UPDATE.
+type HistoryEntryResponse struct {
+	Operation string                   `json:"operation"`
+	ChangedAt time.Time                `json:"changedAt"`
+	Actor     string                   `json:"actor"`
+	Source    string                   `json:"source"`
+	OldValues *SwiftCodeResponseBR     `json:"oldValues"`
+	NewValues *SwiftCodeResponseBR     `json:"newValues"`
+	Changes   []repository.FieldChange `json:"changes,omitempty"`
+}
+
+// GetSwiftCodeHistory zwraca wszystkie zmiany kodu od najstarszej. Historię
+// usuniętego kodu i pola deletedAt/deletedBy widać tylko z WithDeleted.
+func (s *swiftService) GetSwiftCodeHistory(ctx context.Context, code string) (*HistoryResponse, error) {
+	code = NormalizeBIC(code)
+	if fields := ValidateBIC(code, ""); len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	public := !repository.IncludesDeleted(ctx)
+	if public {
+		current, err := s.repo.GetBySwiftCode(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("service error getting swift code history: %w", err)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
+		}
+	}
+
+	entries, err := s.repo.GetHistory(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting swift code history: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
+	}
+
+	resp := &HistoryResponse{SwiftCode: code, History: make([]HistoryEntryResponse, 0, len(entries))}
+	for _, entry := range entries {
+		if public {
+			entry.Old = withoutDeletion(entry.Old)
+			entry.New = withoutDeletion(entry.New)
+		}
+		dto := HistoryEntryResponse{
+			Operation: entry.Operation,
+			ChangedAt: entry.ChangedAt,
+			Actor:     entry.Actor,
+			Source:    entry.Source,
+		}
+		if entry.Old != nil {
+			old := toSwiftCodeResponseBR(*entry.Old)
+			dto.OldValues = &old
+		}
+		if entry.New != nil {
+			updated := toSwiftCodeResponseBR(*entry.New)
+			dto.NewValues = &updated
+		}
+		if entry.Old != nil && entry.New != nil {
+			dto.Changes = repository.DiffSwiftCodes(*entry.Old, *entry.New)
+		}
+		if public && entry.Operation == "UPDATE" && len(dto.Changes) == 0 {
+			// usunięcie i przywrócenie kodu zmieniają tylko deleted_at/deleted_by
+			continue
+		}
+		resp.History = append(resp.History, dto)
+	}
+
+	return resp, nil
+}
+
+// withoutDeletion zwraca kopię stanu rekordu bez informacji o usunięciu.
+func withoutDeletion(sc *repository.SwiftCode) *repository.SwiftCode {
+	if sc == nil {
+		return nil
+	}
+	clean := *sc
+	clean.DeletedAt = sql.NullTime{}
+	clean.DeletedBy = sql.NullString{}
+	return &clean
+}
+
+// GetSwiftCodeAsOf odtwarza odpowiedź GetSwiftCodeWithBranches z chwili asOf
+// na podstawie historii zmian.
+func (s *swiftService) GetSwiftCodeAsOf(ctx context.Context, code string, asOf time.Time) (interface{}, error) {
+	code = NormalizeBIC(code)
+	if fields := ValidateBIC(code, ""); len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	swiftCode, err := s.repo.GetBySwiftCodeAsOf(ctx, code, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting swift code as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+	if swiftCode == nil {
+		return nil, fmt.Errorf("%w: %s as of %s", ErrSwiftCodeNotFound, code, asOf.Format(time.RFC3339))
+	}
+
+	if !swiftCode.IsHeadquarter {
+		brResp := toSwiftCodeResponseBR(*swiftCode)
+		return &brResp, nil
+	}
+
+	branches, err := s.repo.GetBranchesAsOf(ctx, swiftCode.SwiftCode, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting branches as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+	return toSwiftCodeResponseHQ(*swiftCode, branches), nil
+}
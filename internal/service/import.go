@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"swift-codes-api/internal/repository"
+)
+
+type ImportMode string
+
+const (
+	// ImportModeUpsert dodaje i aktualizuje kody, niczego nie usuwając.
+	ImportModeUpsert ImportMode = "upsert"
+	// ImportModeSync traktuje plik jako kompletny katalog i usuwa kody, których w nim nie ma.
+	ImportModeSync ImportMode = "sync"
+)
+
+const DefaultMaxDeletePercent = 10
+
+type ImportOptions struct {
+	Mode             ImportMode
+	MaxDeletePercent float64
+}
+
+func ParseImportMode(mode string) (ImportMode, error) {
+	switch ImportMode(strings.ToLower(strings.TrimSpace(mode))) {
+	case "", ImportModeUpsert:
+		return ImportModeUpsert, nil
+	case ImportModeSync:
+		return ImportModeSync, nil
+	default:
+		return "", fmt.Errorf("unknown import mode %q, expected %q or %q", mode, ImportModeUpsert, ImportModeSync)
+	}
+}
+
+// ImportAction mówi, co zrobić z rekordem; wartość zerowa oznacza dodanie lub aktualizację.
+type ImportAction string
+
+const (
+	ImportActionUpsert ImportAction = ""
+	// ImportActionDelete usuwa kod z bazy; z danych wejściowych liczy się tylko SwiftCode.
+	ImportActionDelete ImportAction = "delete"
+)
+
+// ImportRecord to jeden wiersz pliku źródłowego; Row służy do raportowania błędów.
+type ImportRecord struct {
+	Row    int
+	Action ImportAction
+	Input  CreateSwiftCodeInput
+}
+
+type RejectedRow struct {
+	Row       int          `json:"row"`
+	SwiftCode string       `json:"swiftCode"`
+	Errors    []FieldError `json:"errors"`
+}
+
+type ImportReport struct {
+	TotalRows int           `json:"totalRows"`
+	Inserted  int           `json:"inserted"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Deleted   int           `json:"deleted"`
+	Detached  int           `json:"detached"`
+	Rejected  []RejectedRow `json:"rejected"`
+}
+
+// ImportSwiftCodes waliduje wszystkie wiersze i dopiero gdy każdy jest poprawny,
+// zapisuje je w jednej transakcji. Przy błędach walidacji nic nie jest zapisywane,
+// a raport zawiera listę odrzuconych wierszy.
+func (s *swiftService) ImportSwiftCodes(ctx context.Context, records []ImportRecord, opts ImportOptions) (*ImportReport, error) {
+	report := &ImportReport{TotalRows: len(records), Rejected: []RejectedRow{}}
+
+	repoOpts, err := opts.toRepository()
+	if err != nil {
+		return report, err
+	}
+
+	codes, deletes, rejected := prepareImportRecords(records)
+	if len(rejected) > 0 {
+		report.Rejected = rejected
+		return report, fmt.Errorf("%w: %d of %d rows failed validation", ErrImportRejected, len(rejected), len(records))
+	}
+
+	repoOpts.DeleteCodes = deletes
+	result, err := s.repo.ImportSwiftCodes(ctx, codes, repoOpts)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeleteThresholdExceeded) {
+			return report, fmt.Errorf("%w: %v", ErrDeleteThresholdExceeded, err)
+		}
+		return report, fmt.Errorf("service error importing swift codes: %w", err)
+	}
+
+	report.Inserted = result.Inserted
+	report.Updated = result.Updated
+	report.Unchanged = result.Unchanged
+	report.Deleted = result.Deleted
+	report.Detached = result.Detached
+	return report, nil
+}
+
+func (opts ImportOptions) toRepository() (repository.ImportOptions, error) {
+	mode, err := ParseImportMode(string(opts.Mode))
+	if err != nil {
+		return repository.ImportOptions{}, err
+	}
+	if opts.MaxDeletePercent < 0 || opts.MaxDeletePercent > 100 {
+		return repository.ImportOptions{}, fmt.Errorf("max delete percent must be between 0 and 100, got %v", opts.MaxDeletePercent)
+	}
+
+	return repository.ImportOptions{
+		DeleteMissing:    mode == ImportModeSync,
+		MaxDeletePercent: opts.MaxDeletePercent,
+	}, nil
+}
+
+type ModifiedSwiftCode struct {
+	SwiftCode string                   `json:"swiftCode"`
+	Changes   []repository.FieldChange `json:"changes"`
+}
+
+// ImportDiff opisuje, co zmieniłby import pliku, bez zapisywania czegokolwiek.
+// Removed to kody obecne w bazie, które plik oznacza do usunięcia, a w trybie
+// sync także te, których w pliku nie ma.
+type ImportDiff struct {
+	TotalRows int                 `json:"totalRows"`
+	Added     []SwiftCodeBasic    `json:"added"`
+	Removed   []SwiftCodeBasic    `json:"removed"`
+	Modified  []ModifiedSwiftCode `json:"modified"`
+	Unchanged int                 `json:"unchanged"`
+	Rejected  []RejectedRow       `json:"rejected"`
+}
+
+func (s *swiftService) DiffImport(ctx context.Context, records []ImportRecord, mode ImportMode) (*ImportDiff, error) {
+	mode, err := ParseImportMode(string(mode))
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add("mode", "%v", err)
+		return nil, verr
+	}
+
+	codes, deletes, rejected := prepareImportRecords(records)
+
+	existing, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service error loading swift codes: %w", err)
+	}
+
+	diff := &ImportDiff{
+		TotalRows: len(records),
+		Added:     []SwiftCodeBasic{},
+		Removed:   []SwiftCodeBasic{},
+		Modified:  []ModifiedSwiftCode{},
+		Rejected:  []RejectedRow{},
+	}
+	if rejected != nil {
+		diff.Rejected = rejected
+	}
+
+	current := make(map[string]repository.SwiftCode, len(existing))
+	for _, swift := range existing {
+		current[swift.SwiftCode] = swift
+	}
+
+	inFile := make(map[string]bool, len(codes))
+	for _, swift := range codes {
+		inFile[swift.SwiftCode] = true
+	}
+	// Odrzucony wiersz też wymienia kod, więc nie pokazujemy go jako usuniętego.
+	listed := make(map[string]bool, len(records))
+	for _, record := range records {
+		listed[NormalizeBIC(record.Input.SwiftCode)] = true
+	}
+	flagged := make(map[string]bool, len(deletes))
+	for _, code := range deletes {
+		flagged[code] = true
+	}
+
+	for _, swift := range codes {
+		// Tak jak przy zapisie: oddział bez centrali w pliku i w bazie nie ma headquarter_swift_code.
+		if hq := swift.HeadquarterSwiftCode; hq.Valid && !inFile[hq.String] {
+			if _, ok := current[hq.String]; !ok {
+				swift.HeadquarterSwiftCode = sql.NullString{}
+			}
+		}
+
+		old, ok := current[swift.SwiftCode]
+		if !ok {
+			diff.Added = append(diff.Added, toSwiftCodeBasic(swift))
+			continue
+		}
+		if changes := repository.DiffSwiftCodes(old, swift); len(changes) > 0 {
+			diff.Modified = append(diff.Modified, ModifiedSwiftCode{SwiftCode: swift.SwiftCode, Changes: changes})
+		} else {
+			diff.Unchanged++
+		}
+	}
+
+	// Pliki różnicowe (upsert) usuwają tylko kody z flagą D; brak kodu w pliku
+	// oznacza usunięcie wyłącznie w trybie sync.
+	for _, swift := range existing {
+		if flagged[swift.SwiftCode] || (mode == ImportModeSync && !listed[swift.SwiftCode]) {
+			diff.Removed = append(diff.Removed, toSwiftCodeBasic(swift))
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].SwiftCode < diff.Added[j].SwiftCode })
+	sort.Slice(diff.Modified, func(i, j int) bool { return diff.Modified[i].SwiftCode < diff.Modified[j].SwiftCode })
+
+	return diff, nil
+}
+
+// ValidateImportRecords sprawdza wiersze tak samo jak ImportSwiftCodes, bez dostępu do bazy.
+func ValidateImportRecords(records []ImportRecord) []RejectedRow {
+	_, _, rejected := prepareImportRecords(records)
+	return rejected
+}
+
+// prepareImportRecords zwraca rekordy do zapisania, kody do usunięcia i odrzucone wiersze.
+func prepareImportRecords(records []ImportRecord) ([]repository.SwiftCode, []string, []RejectedRow) {
+	codes := make([]repository.SwiftCode, 0, len(records))
+	var deletes []string
+	var rejected []RejectedRow
+	firstRow := make(map[string]int, len(records))
+
+	for _, record := range records {
+		var swift repository.SwiftCode
+		var err error
+		switch record.Action {
+		case ImportActionUpsert:
+			swift, err = prepareSwiftCode(record.Input)
+		case ImportActionDelete:
+			swift.SwiftCode = NormalizeBIC(record.Input.SwiftCode)
+			if fields := ValidateBIC(swift.SwiftCode, ""); len(fields) > 0 {
+				err = &ValidationError{Fields: fields}
+			}
+		default:
+			err = fmt.Errorf("unknown import action %q", record.Action)
+		}
+		if err != nil {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				validationErr = &ValidationError{}
+				validationErr.add("row", "%v", err)
+			}
+			rejected = append(rejected, RejectedRow{Row: record.Row, SwiftCode: record.Input.SwiftCode, Errors: validationErr.Fields})
+			continue
+		}
+
+		if row, seen := firstRow[swift.SwiftCode]; seen {
+			rejected = append(rejected, RejectedRow{
+				Row:       record.Row,
+				SwiftCode: swift.SwiftCode,
+				Errors:    []FieldError{{Field: "swiftCode", Message: fmt.Sprintf("duplicate of row %d", row)}},
+			})
+			continue
+		}
+		firstRow[swift.SwiftCode] = record.Row
+
+		if record.Action == ImportActionDelete {
+			deletes = append(deletes, swift.SwiftCode)
+			continue
+		}
+		codes = append(codes, swift)
+	}
+
+	return codes, deletes, rejected
+}
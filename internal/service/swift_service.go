@@ -1,61 +1,179 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"swift-codes-api/internal/repository"
 )
 
 type SwiftService interface {
-	GetSwiftCodeWithBranches(code string) (interface{}, error)
-	GetSwiftCodesByCountry(countryISO2 string) (*CountrySwiftCodesResponse, error)
-	CreateSwiftCode(input CreateSwiftCodeInput) error
-	DeleteSwiftCode(code string) error
+	GetSwiftCodeWithBranches(ctx context.Context, code string) (interface{}, error)
+	GetSwiftCodeAsOf(ctx context.Context, code string, asOf time.Time) (interface{}, error)
+	GetSwiftCodeHistory(ctx context.Context, code string) (*HistoryResponse, error)
+	GetSwiftCodesByCountry(ctx context.Context, countryISO2 string, opts CountryListOptions) (*CountrySwiftCodesResponse, error)
+	SearchSwiftCodes(ctx context.Context, opts SearchOptions) (*SearchResponse, error)
+	ListCountries(ctx context.Context, includeEmpty bool) (*CountriesResponse, error)
+	GetCountry(ctx context.Context, countryISO2 string) (*CountryResponse, error)
+	GetBank(ctx context.Context, institutionCode string) (*BankResponse, error)
+	LookupSwiftCodes(ctx context.Context, codes []string) (*LookupResponse, error)
+	CreateSwiftCode(ctx context.Context, input CreateSwiftCodeInput) error
+	ReplaceSwiftCode(ctx context.Context, code string, input CreateSwiftCodeInput) (*ReplaceSwiftCodeResult, error)
+	UpdateSwiftCode(ctx context.Context, code string, input UpdateSwiftCodeInput) (interface{}, error)
+	DeleteSwiftCode(ctx context.Context, code string, policy BranchPolicy) (*DeleteResult, error)
+	RestoreSwiftCode(ctx context.Context, code string) (interface{}, error)
+	PurgeDeletedSwiftCodes(ctx context.Context, olderThan time.Duration) (int, error)
+	BulkCreateSwiftCodes(ctx context.Context, inputs []CreateSwiftCodeInput, mode BulkMode) ([]BulkItemResult, error)
+	BulkDeleteSwiftCodes(ctx context.Context, codes []string, mode BulkMode, policy BranchPolicy) ([]BulkItemResult, error)
+	ImportSwiftCodes(ctx context.Context, records []ImportRecord, opts ImportOptions) (*ImportReport, error)
+	DiffImport(ctx context.Context, records []ImportRecord, mode ImportMode) (*ImportDiff, error)
+	ExportSwiftCodes(ctx context.Context, opts ExportOptions, fn func(repository.SwiftCode) error) error
+}
+
+// UpdateSwiftCodeInput zawiera tylko pola przesłane przez klienta (nil = bez zmian).
+type UpdateSwiftCodeInput struct {
+	SwiftCode            *string
+	BankName             *string
+	Address              *string
+	TownName             *string
+	CountryISO2          *string
+	CountryName          *string
+	IsHeadquarter        *bool
+	HeadquarterSwiftCode *string
+	CodeType             *string
+	TimeZone             *string
+	BranchInformation    *string
+	ZipCode              *string
+	InstitutionType      *string
+}
+
+type ReplaceSwiftCodeResult struct {
+	Created bool                     `json:"created"`
+	Changes []repository.FieldChange `json:"changes"`
 }
 
 type CreateSwiftCodeInput struct {
 	SwiftCode            string
 	BankName             string
 	Address              string
+	TownName             string
 	CountryISO2          string
 	CountryName          string
-	IsHeadquarter        bool
+	IsHeadquarter        *bool
 	HeadquarterSwiftCode *string
+	CodeType             string
+	TimeZone             string
+	BranchInformation    string
+	ZipCode              string
+	InstitutionType      string
 }
 
 type SwiftCodeResponseHQ struct {
-	SwiftCode     string           `json:"swiftCode"`
-	BankName      string           `json:"bankName"`
-	Address       string           `json:"address"`
-	CountryISO2   string           `json:"countryISO2"`
-	CountryName   string           `json:"countryName"`
-	IsHeadquarter bool             `json:"isHeadquarter"`
-	Branches      []SwiftCodeBasic `json:"branches"`
+	SwiftCode         string           `json:"swiftCode"`
+	BankName          string           `json:"bankName"`
+	Address           string           `json:"address"`
+	TownName          string           `json:"townName"`
+	CountryISO2       string           `json:"countryISO2"`
+	CountryName       string           `json:"countryName"`
+	IsHeadquarter     bool             `json:"isHeadquarter"`
+	CodeType          string           `json:"codeType"`
+	TimeZone          string           `json:"timeZone"`
+	BranchInformation string           `json:"branchInformation"`
+	ZipCode           string           `json:"zipCode"`
+	InstitutionType   string           `json:"institutionType"`
+	DeletedAt         *time.Time       `json:"deletedAt,omitempty"`
+	DeletedBy         string           `json:"deletedBy,omitempty"`
+	Branches          []SwiftCodeBasic `json:"branches"`
 }
 
 type SwiftCodeResponseBR struct {
-	SwiftCode     string `json:"swiftCode"`
-	BankName      string `json:"bankName"`
-	Address       string `json:"address"`
-	CountryISO2   string `json:"countryISO2"`
-	CountryName   string `json:"countryName"`
-	IsHeadquarter bool   `json:"isHeadquarter"`
+	SwiftCode         string     `json:"swiftCode"`
+	BankName          string     `json:"bankName"`
+	Address           string     `json:"address"`
+	TownName          string     `json:"townName"`
+	CountryISO2       string     `json:"countryISO2"`
+	CountryName       string     `json:"countryName"`
+	IsHeadquarter     bool       `json:"isHeadquarter"`
+	CodeType          string     `json:"codeType"`
+	TimeZone          string     `json:"timeZone"`
+	BranchInformation string     `json:"branchInformation"`
+	ZipCode           string     `json:"zipCode"`
+	InstitutionType   string     `json:"institutionType"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy         string     `json:"deletedBy,omitempty"`
 }
 
 type SwiftCodeBasic struct {
-	SwiftCode     string `json:"swiftCode"`
-	BankName      string `json:"bankName"`
-	Address       string `json:"address"`
-	CountryISO2   string `json:"countryISO2"`
-	IsHeadquarter bool   `json:"isHeadquarter"`
+	SwiftCode     string     `json:"swiftCode"`
+	BankName      string     `json:"bankName"`
+	Address       string     `json:"address"`
+	TownName      string     `json:"townName"`
+	CountryISO2   string     `json:"countryISO2"`
+	IsHeadquarter bool       `json:"isHeadquarter"`
+	CodeType      string     `json:"codeType"`
+	TimeZone      string     `json:"timeZone"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy     string     `json:"deletedBy,omitempty"`
 }
 
 type CountrySwiftCodesResponse struct {
 	CountryISO2 string           `json:"countryISO2"`
 	CountryName string           `json:"countryName"`
 	SwiftCodes  []SwiftCodeBasic `json:"swiftCodes"`
+	TotalCount  int              `json:"totalCount"`
+	NextCursor  string           `json:"nextCursor,omitempty"`
+	Links       *PageLinks       `json:"links,omitempty"`
+}
+
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
+
+type SwiftCodeSearchResult struct {
+	SwiftCodeBasic
+	Score float64 `json:"score"`
+}
+
+type SearchResponse struct {
+	Query      string                  `json:"query"`
+	TotalCount int                     `json:"totalCount"`
+	Limit      int                     `json:"limit"`
+	Offset     int                     `json:"offset"`
+	Results    []SwiftCodeSearchResult `json:"results"`
+	Links      *PageLinks              `json:"links,omitempty"`
+}
+
+// Kody są zawsze zapisywane jako BIC11 (BIC8 uzupełniamy sufiksem "XXX").
+const DefaultCodeType = "BIC11"
+
+const (
+	DefaultPageLimit   = 100
+	MaxPageLimit       = 1000
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+type SearchOptions struct {
+	Query       string
+	CountryISO2 string
+	Limit       int
+	Offset      int
+}
+
+// CountryListOptions to parametry stronicowania, sortowania i filtrowania listy kodów kraju.
+type CountryListOptions struct {
+	Limit          int
+	Cursor         string
+	Sort           string
+	IsHeadquarter  *bool
+	BankNamePrefix string
 }
 
 type swiftService struct {
@@ -68,111 +186,760 @@ func NewSwiftService(repo repository.SwiftRepository) SwiftService {
 	}
 }
 
-func (s *swiftService) GetSwiftCodeWithBranches(code string) (interface{}, error) {
-	swiftCode, err := s.repo.GetBySwiftCode(code)
+func (s *swiftService) GetSwiftCodeWithBranches(ctx context.Context, code string) (interface{}, error) {
+	code = NormalizeBIC(code)
+	swiftCode, err := s.repo.GetBySwiftCode(ctx, code)
 	if err != nil {
 		return nil, fmt.Errorf("service error getting swift code: %w", err)
 	}
 	if swiftCode == nil {
-		return nil, fmt.Errorf("swift code not found: %s", code)
+		return nil, fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
 	}
 
-	if swiftCode.IsHeadquarter {
-		hqResp := SwiftCodeResponseHQ{
-			SwiftCode:     swiftCode.SwiftCode,
-			BankName:      swiftCode.BankName,
-			Address:       swiftCode.Address,
-			CountryISO2:   swiftCode.CountryISO2,
-			CountryName:   swiftCode.CountryName,
-			IsHeadquarter: swiftCode.IsHeadquarter,
-		}
-		branches, err := s.repo.GetBranchesByHeadquarterCode(swiftCode.SwiftCode)
-		if err != nil {
-			return nil, fmt.Errorf("service error getting branches: %w", err)
-		}
-		for _, branch := range branches {
-			brResp := SwiftCodeBasic{
-				SwiftCode:     branch.SwiftCode,
-				BankName:      branch.BankName,
-				Address:       branch.Address,
-				CountryISO2:   branch.CountryISO2,
-				IsHeadquarter: branch.IsHeadquarter,
-			}
-			hqResp.Branches = append(hqResp.Branches, brResp)
-		}
-		return &hqResp, nil
-	} else {
-		brResp := SwiftCodeResponseBR{
-			SwiftCode:     swiftCode.SwiftCode,
-			BankName:      swiftCode.BankName,
-			Address:       swiftCode.Address,
-			CountryISO2:   swiftCode.CountryISO2,
-			CountryName:   swiftCode.CountryName,
-			IsHeadquarter: swiftCode.IsHeadquarter,
-		}
+	if !swiftCode.IsHeadquarter {
+		brResp := toSwiftCodeResponseBR(*swiftCode)
 		return &brResp, nil
 	}
+
+	branches, err := s.repo.GetBranchesByHeadquarterCode(ctx, swiftCode.SwiftCode)
+	if err != nil {
+		return nil, fmt.Errorf("service error getting branches: %w", err)
+	}
+	return toSwiftCodeResponseHQ(*swiftCode, branches), nil
 }
 
-func (s *swiftService) GetSwiftCodesByCountry(countryISO2 string) (*CountrySwiftCodesResponse, error) {
-	swiftCodes, err := s.repo.GetByCountryISO2(countryISO2)
+func toSwiftCodeResponseHQ(swiftCode repository.SwiftCode, branches []repository.SwiftCode) *SwiftCodeResponseHQ {
+	hqResp := SwiftCodeResponseHQ{
+		SwiftCode:         swiftCode.SwiftCode,
+		BankName:          swiftCode.BankName,
+		Address:           swiftCode.Address,
+		TownName:          swiftCode.TownName,
+		CountryISO2:       swiftCode.CountryISO2,
+		CountryName:       swiftCode.CountryName,
+		IsHeadquarter:     swiftCode.IsHeadquarter,
+		CodeType:          swiftCode.CodeType,
+		TimeZone:          swiftCode.TimeZone,
+		BranchInformation: swiftCode.BranchInformation,
+		ZipCode:           swiftCode.ZipCode,
+		InstitutionType:   swiftCode.InstitutionType,
+		DeletedAt:         deletedAt(swiftCode),
+		DeletedBy:         swiftCode.DeletedBy.String,
+	}
+	for _, branch := range branches {
+		hqResp.Branches = append(hqResp.Branches, toSwiftCodeBasic(branch))
+	}
+	return &hqResp
+}
+
+func (s *swiftService) GetSwiftCodesByCountry(ctx context.Context, countryISO2 string, opts CountryListOptions) (*CountrySwiftCodesResponse, error) {
+	countryISO2 = strings.ToUpper(strings.TrimSpace(countryISO2))
+
+	query, err := opts.toQuery(countryISO2)
+	if err != nil {
+		return nil, err
+	}
+
+	country, err := s.lookupCountry(ctx, countryISO2)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.CountByCountryISO2(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("service error counting swift codes by country: %w", err)
+	}
+
+	// 404 oznacza kraj bez żadnych kodów; filtry, którym nic nie odpowiada,
+	// dają zwykłą pustą stronę.
+	if total == 0 && query.IsHeadquarter == nil && query.BankNamePrefix == "" {
+		return nil, fmt.Errorf("%w: %s", ErrCountryNotFound, countryISO2)
+	}
+
+	// Pobieramy jeden rekord więcej, żeby wiedzieć, czy istnieje kolejna strona.
+	limit := query.Limit
+	query.Limit++
+	swiftCodes, err := s.repo.GetByCountryISO2(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("service error getting swift codes by country: %w", err)
 	}
 
-	if len(swiftCodes) == 0 {
-		return nil, fmt.Errorf("no swift codes found for country: %s", countryISO2)
+	var nextCursor string
+	if len(swiftCodes) > limit {
+		swiftCodes = swiftCodes[:limit]
+		nextCursor = encodeCursor(query.SortBy, swiftCodes[limit-1])
 	}
 
-	var dtos []SwiftCodeBasic
+	dtos := make([]SwiftCodeBasic, 0, len(swiftCodes))
 	for _, sc := range swiftCodes {
-		dtos = append(dtos, SwiftCodeBasic{
-			SwiftCode:     sc.SwiftCode,
-			BankName:      sc.BankName,
-			Address:       sc.Address,
-			CountryISO2:   sc.CountryISO2,
-			IsHeadquarter: sc.IsHeadquarter,
-		})
+		dtos = append(dtos, toSwiftCodeBasic(sc))
 	}
 
 	return &CountrySwiftCodesResponse{
 		CountryISO2: countryISO2,
-		CountryName: swiftCodes[0].CountryName,
+		CountryName: country.Name,
 		SwiftCodes:  dtos,
+		TotalCount:  total,
+		NextCursor:  nextCursor,
 	}, nil
 }
 
-func (s *swiftService) CreateSwiftCode(input CreateSwiftCodeInput) error {
-	countryISO2 := strings.ToUpper(input.CountryISO2)
-	countryName := strings.ToUpper(input.CountryName)
+func (s *swiftService) SearchSwiftCodes(ctx context.Context, opts SearchOptions) (*SearchResponse, error) {
+	query, err := opts.toQuery()
+	if err != nil {
+		return nil, err
+	}
 
-	swift := repository.SwiftCode{
-		SwiftCode:     input.SwiftCode,
-		BankName:      input.BankName,
-		Address:       input.Address,
-		CountryISO2:   countryISO2,
-		CountryName:   countryName,
-		IsHeadquarter: input.IsHeadquarter,
+	results, total, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("service error searching swift codes: %w", err)
+	}
+
+	resp := &SearchResponse{
+		Query:      query.Text,
+		TotalCount: total,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+		Results:    make([]SwiftCodeSearchResult, 0, len(results)),
+	}
+	for _, result := range results {
+		resp.Results = append(resp.Results, SwiftCodeSearchResult{
+			SwiftCodeBasic: toSwiftCodeBasic(result.SwiftCode),
+			Score:          result.Score,
+		})
+	}
+
+	return resp, nil
+}
+
+func (opts SearchOptions) toQuery() (repository.SearchQuery, error) {
+	verr := &ValidationError{}
+	query := repository.SearchQuery{
+		Text:        strings.TrimSpace(opts.Query),
+		CountryISO2: strings.ToUpper(strings.TrimSpace(opts.CountryISO2)),
+		Limit:       opts.Limit,
+		Offset:      opts.Offset,
+	}
+
+	if len([]rune(query.Text)) < 2 {
+		verr.add("q", "must be at least 2 characters long")
+	}
+	if query.CountryISO2 != "" && (len(query.CountryISO2) != 2 || !isAlpha(query.CountryISO2)) {
+		verr.add("countryISO2", "must consist of 2 letters")
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	} else if query.Limit < 1 || query.Limit > MaxSearchLimit {
+		verr.add("limit", "must be between 1 and %d", MaxSearchLimit)
+	}
+	if query.Offset < 0 {
+		verr.add("offset", "must not be negative")
+	}
+
+	return query, verr.errOrNil()
+}
+
+func (opts CountryListOptions) toQuery(countryISO2 string) (repository.CountryQuery, error) {
+	verr := &ValidationError{}
+	query := repository.CountryQuery{
+		CountryISO2:    countryISO2,
+		IsHeadquarter:  opts.IsHeadquarter,
+		BankNamePrefix: strings.TrimSpace(opts.BankNamePrefix),
+		SortBy:         repository.SortBySwiftCode,
+		Limit:          opts.Limit,
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultPageLimit
+	} else if query.Limit < 1 || query.Limit > MaxPageLimit {
+		verr.add("limit", "must be between 1 and %d", MaxPageLimit)
+	}
+
+	switch repository.SortField(opts.Sort) {
+	case "", repository.SortBySwiftCode:
+	case repository.SortByBankName:
+		query.SortBy = repository.SortByBankName
+	default:
+		verr.add("sort", "must be one of %q, %q", repository.SortBySwiftCode, repository.SortByBankName)
+	}
+
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor)
+		if err != nil || c.SortBy != query.SortBy {
+			verr.add("cursor", "is invalid or does not match the requested sort")
+		} else {
+			query.AfterSortValue = c.Value
+			query.AfterSwiftCode = c.SwiftCode
+		}
+	}
+
+	return query, verr.errOrNil()
+}
+
+func toSwiftCodeResponseBR(sc repository.SwiftCode) SwiftCodeResponseBR {
+	return SwiftCodeResponseBR{
+		SwiftCode:         sc.SwiftCode,
+		BankName:          sc.BankName,
+		Address:           sc.Address,
+		TownName:          sc.TownName,
+		CountryISO2:       sc.CountryISO2,
+		CountryName:       sc.CountryName,
+		IsHeadquarter:     sc.IsHeadquarter,
+		CodeType:          sc.CodeType,
+		TimeZone:          sc.TimeZone,
+		BranchInformation: sc.BranchInformation,
+		ZipCode:           sc.ZipCode,
+		InstitutionType:   sc.InstitutionType,
+		DeletedAt:         deletedAt(sc),
+		DeletedBy:         sc.DeletedBy.String,
+	}
+}
+
+func toSwiftCodeBasic(sc repository.SwiftCode) SwiftCodeBasic {
+	return SwiftCodeBasic{
+		SwiftCode:     sc.SwiftCode,
+		BankName:      sc.BankName,
+		Address:       sc.Address,
+		TownName:      sc.TownName,
+		CountryISO2:   sc.CountryISO2,
+		IsHeadquarter: sc.IsHeadquarter,
+		CodeType:      sc.CodeType,
+		TimeZone:      sc.TimeZone,
+		DeletedAt:     deletedAt(sc),
+		DeletedBy:     sc.DeletedBy.String,
+	}
+}
+
+// deletedAt zwraca czas usunięcia albo nil dla aktywnego rekordu (pole pomijane w JSON).
+func deletedAt(sc repository.SwiftCode) *time.Time {
+	if !sc.DeletedAt.Valid {
+		return nil
+	}
+	return &sc.DeletedAt.Time
+}
+
+// pageCursor jest nieprzezroczystym dla klienta kursorem kolejnej strony.
+type pageCursor struct {
+	SortBy    repository.SortField `json:"s"`
+	Value     string               `json:"v,omitempty"`
+	SwiftCode string               `json:"c"`
+}
+
+func encodeCursor(sortBy repository.SortField, last repository.SwiftCode) string {
+	c := pageCursor{SortBy: sortBy, SwiftCode: last.SwiftCode}
+	if sortBy == repository.SortByBankName {
+		c.Value = last.BankName
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	if c.SwiftCode == "" {
+		return c, errors.New("cursor without swift code")
+	}
+	return c, nil
+}
+
+// MaxLookupCodes ogranicza liczbę kodów w jednym zapytaniu zbiorczym.
+const MaxLookupCodes = 1000
+
+type LookupResponse struct {
+	Found    []SwiftCodeResponseBR `json:"found"`
+	NotFound []string              `json:"notFound"`
+}
+
+// LookupSwiftCodes wyszukuje wiele kodów jednym zapytaniem do bazy. Kody są
+// normalizowane jak przy pojedynczym odczycie (BIC8 = centrala), duplikaty
+// pomijane, a wyniki zwracane w kolejności z zapytania.
+func (s *swiftService) LookupSwiftCodes(ctx context.Context, codes []string) (*LookupResponse, error) {
+	verr := &ValidationError{}
+	if len(codes) == 0 {
+		verr.add("swiftCodes", "must contain at least one code")
+	} else if len(codes) > MaxLookupCodes {
+		verr.add("swiftCodes", "must contain at most %d codes, got %d", MaxLookupCodes, len(codes))
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		code = NormalizeBIC(code)
+		if !seen[code] {
+			seen[code] = true
+			normalized = append(normalized, code)
+		}
+	}
+
+	swiftCodes, err := s.repo.GetBySwiftCodes(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("service error looking up swift codes: %w", err)
+	}
+
+	byCode := make(map[string]repository.SwiftCode, len(swiftCodes))
+	for _, swift := range swiftCodes {
+		byCode[swift.SwiftCode] = swift
+	}
+
+	response := &LookupResponse{Found: []SwiftCodeResponseBR{}, NotFound: []string{}}
+	for _, code := range normalized {
+		if swift, ok := byCode[code]; ok {
+			response.Found = append(response.Found, toSwiftCodeResponseBR(swift))
+		} else {
+			response.NotFound = append(response.NotFound, code)
+		}
+	}
+
+	return response, nil
+}
+
+// ExportOptions filtruje eksport; puste pola oznaczają wszystkie kody.
+type ExportOptions struct {
+	CountryISO2   string
+	IsHeadquarter *bool
+}
+
+// ExportSwiftCodes przekazuje kolejne kody do fn, nie ładując całego zbioru do pamięci.
+func (s *swiftService) ExportSwiftCodes(ctx context.Context, opts ExportOptions, fn func(repository.SwiftCode) error) error {
+	countryISO2 := strings.ToUpper(strings.TrimSpace(opts.CountryISO2))
+	if countryISO2 != "" && (len(countryISO2) != 2 || !isAlpha(countryISO2)) {
+		verr := &ValidationError{}
+		verr.add("countryISO2", "must consist of 2 letters")
+		return verr
+	}
+
+	query := repository.ExportQuery{CountryISO2: countryISO2, IsHeadquarter: opts.IsHeadquarter}
+	if err := s.repo.StreamSwiftCodes(ctx, query, fn); err != nil {
+		return fmt.Errorf("service error exporting swift codes: %w", err)
+	}
+	return nil
+}
+
+func (s *swiftService) CreateSwiftCode(ctx context.Context, input CreateSwiftCodeInput) error {
+	swift, err := prepareSwiftCode(input)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		return createSwiftCode(ctx, repo, swift)
+	})
+}
+
+func createSwiftCode(ctx context.Context, repo repository.SwiftRepository, swift repository.SwiftCode) error {
+	err := repo.CreateSwiftCode(ctx, swift)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateSwiftCode) {
+			return fmt.Errorf("%w: %s", ErrSwiftCodeExists, swift.SwiftCode)
+		}
+		return fmt.Errorf("service error creating swift code: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceSwiftCode tworzy albo w całości nadpisuje rekord o podanym kodzie.
+// Kod w treści żądania jest opcjonalny, ale jeśli go podano, musi zgadzać się z kodem z URL.
+func (s *swiftService) ReplaceSwiftCode(ctx context.Context, code string, input CreateSwiftCodeInput) (*ReplaceSwiftCodeResult, error) {
+	code = NormalizeBIC(code)
+	if strings.TrimSpace(input.SwiftCode) == "" {
+		input.SwiftCode = code
+	} else if NormalizeBIC(input.SwiftCode) != code {
+		verr := &ValidationError{}
+		verr.add("swiftCode", "must match the code in the URL (%s)", code)
+		return nil, verr
 	}
 
+	swift, err := prepareSwiftCode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var created bool
+	var changes []repository.FieldChange
+	err = s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		var err error
+		created, changes, err = repo.UpsertSwiftCode(ctx, swift)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateSwiftCode) {
+			return nil, fmt.Errorf("%w: %s", ErrSwiftCodeExists, swift.SwiftCode)
+		}
+		return nil, fmt.Errorf("service error replacing swift code: %w", err)
+	}
+
+	if changes == nil {
+		changes = []repository.FieldChange{}
+	}
+	return &ReplaceSwiftCodeResult{Created: created, Changes: changes}, nil
+}
+
+// UpdateSwiftCode nakłada przesłane pola na istniejący rekord, waliduje wynik
+// tak samo jak przy tworzeniu i zapisuje wyłącznie przesłane kolumny.
+func (s *swiftService) UpdateSwiftCode(ctx context.Context, code string, input UpdateSwiftCodeInput) (interface{}, error) {
+	code = NormalizeBIC(code)
+	if input.SwiftCode != nil && NormalizeBIC(*input.SwiftCode) != code {
+		verr := &ValidationError{}
+		verr.add("swiftCode", "cannot be changed (%s)", code)
+		return nil, verr
+	}
+
+	// Rekord czytamy z blokadą w tej samej transakcji co zapis, żeby równoległe
+	// PATCH-e nie nadpisywały sobie nawzajem zmian.
+	err := s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		existing, err := repo.GetBySwiftCodeForUpdate(ctx, code)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
+		}
+
+		update, err := mergeSwiftCodeUpdate(*existing, input)
+		if err != nil {
+			return err
+		}
+		return repo.UpdateSwiftCode(ctx, code, update)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
+		}
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrValidation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service error updating swift code: %w", err)
+	}
+
+	return s.GetSwiftCodeWithBranches(ctx, code)
+}
+
+// mergeSwiftCodeUpdate nakłada przesłane pola na zapisany rekord, waliduje wynik
+// i zwraca zmianę obejmującą tylko przesłane kolumny.
+func mergeSwiftCodeUpdate(existing repository.SwiftCode, input UpdateSwiftCodeInput) (repository.SwiftCodeUpdate, error) {
+	merged := CreateSwiftCodeInput{
+		SwiftCode:            existing.SwiftCode,
+		BankName:             existing.BankName,
+		Address:              existing.Address,
+		TownName:             existing.TownName,
+		CountryISO2:          existing.CountryISO2,
+		CountryName:          existing.CountryName,
+		IsHeadquarter:        input.IsHeadquarter,
+		HeadquarterSwiftCode: input.HeadquarterSwiftCode,
+		CodeType:             existing.CodeType,
+		TimeZone:             existing.TimeZone,
+		BranchInformation:    existing.BranchInformation,
+		ZipCode:              existing.ZipCode,
+		InstitutionType:      existing.InstitutionType,
+	}
+	if input.BankName != nil {
+		merged.BankName = *input.BankName
+	}
+	if input.Address != nil {
+		merged.Address = *input.Address
+	}
+	if input.TownName != nil {
+		merged.TownName = *input.TownName
+	}
+	if input.CodeType != nil {
+		merged.CodeType = *input.CodeType
+	}
+	if input.TimeZone != nil {
+		merged.TimeZone = *input.TimeZone
+	}
+	if input.BranchInformation != nil {
+		merged.BranchInformation = *input.BranchInformation
+	}
+	if input.ZipCode != nil {
+		merged.ZipCode = *input.ZipCode
+	}
+	if input.InstitutionType != nil {
+		merged.InstitutionType = *input.InstitutionType
+	}
+	if input.CountryISO2 != nil {
+		merged.CountryISO2 = *input.CountryISO2
+	}
+	if input.CountryName != nil {
+		merged.CountryName = *input.CountryName
+	}
+
+	swift, err := prepareSwiftCode(merged)
+	if err != nil {
+		return repository.SwiftCodeUpdate{}, err
+	}
+
+	var update repository.SwiftCodeUpdate
+	if input.BankName != nil {
+		update.BankName = &swift.BankName
+	}
+	if input.Address != nil {
+		update.Address = &swift.Address
+	}
+	if input.TownName != nil {
+		update.TownName = &swift.TownName
+	}
+	if input.CodeType != nil {
+		update.CodeType = &swift.CodeType
+	}
+	if input.TimeZone != nil {
+		update.TimeZone = &swift.TimeZone
+	}
+	if input.BranchInformation != nil {
+		update.BranchInformation = &swift.BranchInformation
+	}
+	if input.ZipCode != nil {
+		update.ZipCode = &swift.ZipCode
+	}
+	if input.InstitutionType != nil {
+		update.InstitutionType = &swift.InstitutionType
+	}
+	if input.CountryISO2 != nil {
+		update.CountryISO2 = &swift.CountryISO2
+	}
+	if input.CountryName != nil {
+		update.CountryName = &swift.CountryName
+	}
+	if input.IsHeadquarter != nil {
+		update.IsHeadquarter = &swift.IsHeadquarter
+	}
 	if input.HeadquarterSwiftCode != nil {
-		swift.HeadquarterSwiftCode.String = *input.HeadquarterSwiftCode
-		swift.HeadquarterSwiftCode.Valid = true
-	} else {
-		swift.HeadquarterSwiftCode.Valid = false
+		update.HeadquarterSwiftCode = &swift.HeadquarterSwiftCode
 	}
 
-	return s.repo.CreateSwiftCode(swift)
+	return update, nil
 }
 
-func (s *swiftService) DeleteSwiftCode(code string) error {
-	err := s.repo.DeleteBySwiftCode(code)
+// prepareSwiftCode normalizuje i waliduje dane wejściowe, a flagę centrali
+// oraz kod centrali wyprowadza z sufiksu "XXX" zamiast ufać klientowi.
+func prepareSwiftCode(input CreateSwiftCodeInput) (repository.SwiftCode, error) {
+	input.SwiftCode = NormalizeBIC(input.SwiftCode)
+	input.CountryISO2 = strings.ToUpper(strings.TrimSpace(input.CountryISO2))
+	input.CountryName = strings.ToUpper(input.CountryName)
+	input.CodeType = strings.ToUpper(strings.TrimSpace(input.CodeType))
+	if input.CodeType == "" {
+		input.CodeType = DefaultCodeType
+	}
+
+	if err := validateCreateInput(input); err != nil {
+		return repository.SwiftCode{}, err
+	}
+
+	swift := repository.SwiftCode{
+		SwiftCode:         input.SwiftCode,
+		BankName:          input.BankName,
+		Address:           input.Address,
+		TownName:          strings.TrimSpace(input.TownName),
+		CountryISO2:       input.CountryISO2,
+		CountryName:       input.CountryName,
+		IsHeadquarter:     isHeadquarterCode(input.SwiftCode),
+		CodeType:          input.CodeType,
+		TimeZone:          strings.TrimSpace(input.TimeZone),
+		BranchInformation: strings.TrimSpace(input.BranchInformation),
+		ZipCode:           strings.TrimSpace(input.ZipCode),
+		InstitutionType:   strings.ToUpper(strings.TrimSpace(input.InstitutionType)),
+	}
+
+	if !swift.IsHeadquarter {
+		swift.HeadquarterSwiftCode = sql.NullString{String: headquarterCodeFor(input.SwiftCode), Valid: true}
+	}
+
+	return swift, nil
+}
+
+// NormalizeBIC zamienia kod na wielkie litery, a BIC8 uzupełnia do BIC11
+// sufiksem "XXX" (kod centrali).
+func NormalizeBIC(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) == 8 {
+		code += "XXX"
+	}
+	return code
+}
+
+func isHeadquarterCode(code string) bool {
+	return strings.HasSuffix(code, "XXX")
+}
+
+func headquarterCodeFor(code string) string {
+	return code[:8] + "XXX"
+}
+
+// BranchPolicy określa, co dzieje się z oddziałami usuwanej centrali.
+type BranchPolicy string
+
+const (
+	// BranchPolicyRestrict odmawia usunięcia centrali, która ma oddziały.
+	BranchPolicyRestrict BranchPolicy = "restrict"
+	// BranchPolicyCascade usuwa centralę razem z oddziałami.
+	BranchPolicyCascade BranchPolicy = "branches"
+	// BranchPolicyDetach usuwa centralę, a oddziały zostawia bez centrali.
+	BranchPolicyDetach BranchPolicy = "detach"
+)
+
+// ParseBranchPolicy zamienia wartość parametru "cascade" na politykę; pusta
+// wartość oznacza BranchPolicyRestrict.
+func ParseBranchPolicy(policy string) (BranchPolicy, error) {
+	switch p := BranchPolicy(policy); p {
+	case "":
+		return BranchPolicyRestrict, nil
+	case BranchPolicyRestrict, BranchPolicyCascade, BranchPolicyDetach:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown cascade policy %q, expected %q, %q or %q", policy, BranchPolicyCascade, BranchPolicyRestrict, BranchPolicyDetach)
+	}
+}
+
+func validateBranchPolicy(policy BranchPolicy) (BranchPolicy, error) {
+	parsed, err := ParseBranchPolicy(string(policy))
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add("cascade", "must be %q, %q or %q", BranchPolicyCascade, BranchPolicyRestrict, BranchPolicyDetach)
+		return "", verr
+	}
+	return parsed, nil
+}
+
+type DeleteResult struct {
+	DeletedBranches  int `json:"deletedBranches"`
+	DetachedBranches int `json:"detachedBranches"`
+}
+
+func (s *swiftService) DeleteSwiftCode(ctx context.Context, code string, policy BranchPolicy) (*DeleteResult, error) {
+	policy, err := validateBranchPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *DeleteResult
+	err = s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		var err error
+		result, err = deleteSwiftCode(ctx, repo, NormalizeBIC(code), policy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// deleteSwiftCode usuwa kod, a w przypadku centrali najpierw stosuje politykę
+// wobec jej oddziałów; wywoływane w transakcji, żeby obie zmiany były atomowe.
+func deleteSwiftCode(ctx context.Context, repo repository.SwiftRepository, code string, policy BranchPolicy) (*DeleteResult, error) {
+	result := &DeleteResult{}
+
+	if isHeadquarterCode(code) {
+		var err error
+		switch policy {
+		case BranchPolicyCascade:
+			result.DeletedBranches, err = repo.DeleteBranches(ctx, code)
+		case BranchPolicyDetach:
+			result.DetachedBranches, err = repo.DetachBranches(ctx, code)
+		default:
+			var branches int
+			branches, err = repo.CountBranches(ctx, code)
+			if err == nil && branches > 0 {
+				return nil, fmt.Errorf("%w: %s has %d branches, use cascade=%s or cascade=%s",
+					ErrSwiftCodeHasBranches, code, branches, BranchPolicyCascade, BranchPolicyDetach)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("service error handling branches: %w", err)
+		}
+	}
+
+	err := repo.DeleteBySwiftCode(ctx, code)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("swift code not found: %s", code)
+			return nil, fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
 		}
-		return fmt.Errorf("service error deleting swift code: %w", err)
+		return nil, fmt.Errorf("service error deleting swift code: %w", err)
 	}
 
-	return nil
+	return result, nil
+}
+
+// RestoreSwiftCode przywraca usunięty kod i zwraca go tak jak GetSwiftCodeWithBranches.
+func (s *swiftService) RestoreSwiftCode(ctx context.Context, code string) (interface{}, error) {
+	code = NormalizeBIC(code)
+	if fields := ValidateBIC(code, ""); len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	err := s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		err := repo.RestoreSwiftCode(ctx, code)
+		if err == nil {
+			return relinkRestoredBranch(ctx, repo, code)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		active, err := repo.GetBySwiftCode(ctx, code)
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			return fmt.Errorf("%w: %s", ErrSwiftCodeNotDeleted, code)
+		}
+		return fmt.Errorf("%w: %s", ErrSwiftCodeNotFound, code)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service error restoring swift code: %w", err)
+	}
+
+	return s.GetSwiftCodeWithBranches(ctx, code)
+}
+
+// relinkRestoredBranch ustawia centralę przywróconego oddziału według stanu
+// z chwili przywrócenia: oddział usunięty razem z centralą (cascade=branches)
+// nie może wskazywać centrali, która nadal jest usunięta.
+func relinkRestoredBranch(ctx context.Context, repo repository.SwiftRepository, code string) error {
+	if isHeadquarterCode(code) {
+		return nil
+	}
+
+	hqCode := headquarterCodeFor(code)
+	hq, err := repo.GetBySwiftCode(ctx, hqCode)
+	if err != nil {
+		return err
+	}
+
+	var link sql.NullString
+	if hq != nil {
+		link = sql.NullString{String: hqCode, Valid: true}
+	}
+	return repo.UpdateSwiftCode(ctx, code, repository.SwiftCodeUpdate{HeadquarterSwiftCode: &link})
+}
+
+// PurgeDeletedSwiftCodes fizycznie usuwa kody usunięte dawniej niż olderThan temu.
+func (s *swiftService) PurgeDeletedSwiftCodes(ctx context.Context, olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		verr := &ValidationError{}
+		verr.add("olderThan", "must not be negative")
+		return 0, verr
+	}
+
+	var purged int
+	err := s.repo.WithTx(ctx, func(repo repository.SwiftRepository) error {
+		var err error
+		purged, err = repo.PurgeDeleted(ctx, time.Now().Add(-olderThan))
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("service error purging deleted swift codes: %w", err)
+	}
+	return purged, nil
 }
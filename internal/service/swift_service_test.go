@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"testing"
+	"time"
 
 	"swift-codes-api/internal/repository"
 
@@ -11,32 +14,141 @@ import (
 
 type mockSwiftRepo struct {
 	GetBySwiftCodeFunc               func(code string) (*repository.SwiftCode, error)
-	GetByCountryISO2Func             func(countryISO2 string) ([]repository.SwiftCode, error)
+	GetBySwiftCodeForUpdateFunc      func(code string) (*repository.SwiftCode, error)
+	GetByCountryISO2Func             func(q repository.CountryQuery) ([]repository.SwiftCode, error)
+	CountByCountryISO2Func           func(q repository.CountryQuery) (int, error)
+	GetCountryFunc                   func(iso2 string) (*repository.Country, error)
+	ListCountriesFunc                func(includeEmpty bool) ([]repository.Country, error)
 	GetBranchesByHeadquarterCodeFunc func(hqCode string) ([]repository.SwiftCode, error)
+	GetByInstitutionCodeFunc         func(institutionCode string) ([]repository.SwiftCode, error)
+	CountBranchesFunc                func(hqCode string) (int, error)
+	DeleteBranchesFunc               func(hqCode string) (int, error)
+	DetachBranchesFunc               func(hqCode string) (int, error)
+	SearchFunc                       func(q repository.SearchQuery) ([]repository.SearchResult, int, error)
 	CreateSwiftCodeFunc              func(swift repository.SwiftCode) error
+	UpsertSwiftCodeFunc              func(swift repository.SwiftCode) (bool, []repository.FieldChange, error)
+	UpdateSwiftCodeFunc              func(code string, update repository.SwiftCodeUpdate) error
 	DeleteBySwiftCodeFunc            func(code string) error
+	ImportSwiftCodesFunc             func(codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error)
+	GetAllFunc                       func() ([]repository.SwiftCode, error)
+	GetHistoryFunc                   func(code string) ([]repository.HistoryEntry, error)
+	GetBySwiftCodeAsOfFunc           func(code string, asOf time.Time) (*repository.SwiftCode, error)
+	GetBranchesAsOfFunc              func(hqCode string, asOf time.Time) ([]repository.SwiftCode, error)
+	GetBySwiftCodesFunc              func(codes []string) ([]repository.SwiftCode, error)
+	RestoreSwiftCodeFunc             func(code string) error
+	PurgeDeletedFunc                 func(before time.Time) (int, error)
+	WithTxFunc                       func(fn func(repo repository.SwiftRepository) error) error
+	StreamSwiftCodesFunc             func(q repository.ExportQuery, fn func(repository.SwiftCode) error) error
 }
 
-func (m *mockSwiftRepo) GetBySwiftCode(code string) (*repository.SwiftCode, error) {
+func (m *mockSwiftRepo) GetBySwiftCode(ctx context.Context, code string) (*repository.SwiftCode, error) {
 	return m.GetBySwiftCodeFunc(code)
 }
 
-func (m *mockSwiftRepo) GetByCountryISO2(countryISO2 string) ([]repository.SwiftCode, error) {
-	return m.GetByCountryISO2Func(countryISO2)
+func (m *mockSwiftRepo) GetByCountryISO2(ctx context.Context, q repository.CountryQuery) ([]repository.SwiftCode, error) {
+	return m.GetByCountryISO2Func(q)
 }
 
-func (m *mockSwiftRepo) GetBranchesByHeadquarterCode(hqCode string) ([]repository.SwiftCode, error) {
+func (m *mockSwiftRepo) CountByCountryISO2(ctx context.Context, q repository.CountryQuery) (int, error) {
+	return m.CountByCountryISO2Func(q)
+}
+
+func (m *mockSwiftRepo) GetCountry(ctx context.Context, iso2 string) (*repository.Country, error) {
+	return m.GetCountryFunc(iso2)
+}
+
+func (m *mockSwiftRepo) ListCountries(ctx context.Context, includeEmpty bool) ([]repository.Country, error) {
+	return m.ListCountriesFunc(includeEmpty)
+}
+
+func (m *mockSwiftRepo) GetBranchesByHeadquarterCode(ctx context.Context, hqCode string) ([]repository.SwiftCode, error) {
 	return m.GetBranchesByHeadquarterCodeFunc(hqCode)
 }
 
-func (m *mockSwiftRepo) CreateSwiftCode(swift repository.SwiftCode) error {
+func (m *mockSwiftRepo) GetByInstitutionCode(ctx context.Context, institutionCode string) ([]repository.SwiftCode, error) {
+	return m.GetByInstitutionCodeFunc(institutionCode)
+}
+
+func (m *mockSwiftRepo) CountBranches(ctx context.Context, hqCode string) (int, error) {
+	return m.CountBranchesFunc(hqCode)
+}
+
+func (m *mockSwiftRepo) DeleteBranches(ctx context.Context, hqCode string) (int, error) {
+	return m.DeleteBranchesFunc(hqCode)
+}
+
+func (m *mockSwiftRepo) DetachBranches(ctx context.Context, hqCode string) (int, error) {
+	return m.DetachBranchesFunc(hqCode)
+}
+
+func (m *mockSwiftRepo) Search(ctx context.Context, q repository.SearchQuery) ([]repository.SearchResult, int, error) {
+	return m.SearchFunc(q)
+}
+
+func (m *mockSwiftRepo) CreateSwiftCode(ctx context.Context, swift repository.SwiftCode) error {
 	return m.CreateSwiftCodeFunc(swift)
 }
 
-func (m *mockSwiftRepo) DeleteBySwiftCode(code string) error {
+func (m *mockSwiftRepo) UpsertSwiftCode(ctx context.Context, swift repository.SwiftCode) (bool, []repository.FieldChange, error) {
+	return m.UpsertSwiftCodeFunc(swift)
+}
+
+func (m *mockSwiftRepo) UpdateSwiftCode(ctx context.Context, code string, update repository.SwiftCodeUpdate) error {
+	return m.UpdateSwiftCodeFunc(code, update)
+}
+
+func (m *mockSwiftRepo) DeleteBySwiftCode(ctx context.Context, code string) error {
 	return m.DeleteBySwiftCodeFunc(code)
 }
 
+func (m *mockSwiftRepo) ImportSwiftCodes(ctx context.Context, codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error) {
+	return m.ImportSwiftCodesFunc(codes, opts)
+}
+
+// WithTx domyślnie wywołuje fn na tym samym mocku, bez prawdziwej transakcji.
+func (m *mockSwiftRepo) GetHistory(ctx context.Context, code string) ([]repository.HistoryEntry, error) {
+	return m.GetHistoryFunc(code)
+}
+
+func (m *mockSwiftRepo) GetBySwiftCodeAsOf(ctx context.Context, code string, asOf time.Time) (*repository.SwiftCode, error) {
+	return m.GetBySwiftCodeAsOfFunc(code, asOf)
+}
+
+func (m *mockSwiftRepo) GetBranchesAsOf(ctx context.Context, hqCode string, asOf time.Time) ([]repository.SwiftCode, error) {
+	return m.GetBranchesAsOfFunc(hqCode, asOf)
+}
+
+func (m *mockSwiftRepo) GetBySwiftCodeForUpdate(ctx context.Context, code string) (*repository.SwiftCode, error) {
+	return m.GetBySwiftCodeForUpdateFunc(code)
+}
+
+func (m *mockSwiftRepo) RestoreSwiftCode(ctx context.Context, code string) error {
+	return m.RestoreSwiftCodeFunc(code)
+}
+
+func (m *mockSwiftRepo) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	return m.PurgeDeletedFunc(before)
+}
+
+func (m *mockSwiftRepo) WithTx(ctx context.Context, fn func(repo repository.SwiftRepository) error) error {
+	if m.WithTxFunc != nil {
+		return m.WithTxFunc(fn)
+	}
+	return fn(m)
+}
+
+func (m *mockSwiftRepo) GetBySwiftCodes(ctx context.Context, codes []string) ([]repository.SwiftCode, error) {
+	return m.GetBySwiftCodesFunc(codes)
+}
+
+func (m *mockSwiftRepo) GetAll(ctx context.Context) ([]repository.SwiftCode, error) {
+	return m.GetAllFunc()
+}
+
+func (m *mockSwiftRepo) StreamSwiftCodes(ctx context.Context, q repository.ExportQuery, fn func(repository.SwiftCode) error) error {
+	return m.StreamSwiftCodesFunc(q, fn)
+}
+
 func TestGetSwiftCodeWithBranches_HQ(t *testing.T) {
 	mockRepo := &mockSwiftRepo{
 		GetBySwiftCodeFunc: func(code string) (*repository.SwiftCode, error) {
@@ -75,7 +187,7 @@ func TestGetSwiftCodeWithBranches_HQ(t *testing.T) {
 	}
 
 	svc := NewSwiftService(mockRepo)
-	result, err := svc.GetSwiftCodeWithBranches("HQCODEXXX")
+	result, err := svc.GetSwiftCodeWithBranches(context.Background(), "HQCODEXXX")
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 
@@ -109,7 +221,7 @@ func TestGetSwiftCodeWithBranches_Branch(t *testing.T) {
 	}
 
 	svc := NewSwiftService(mockRepo)
-	result, err := svc.GetSwiftCodeWithBranches("BRANCHCODEXXX")
+	result, err := svc.GetSwiftCodeWithBranches(context.Background(), "BRANCHCODEXXX")
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 
@@ -120,7 +232,13 @@ func TestGetSwiftCodeWithBranches_Branch(t *testing.T) {
 
 func TestGetSwiftCodesByCountry_Success(t *testing.T) {
 	mockRepo := &mockSwiftRepo{
-		GetByCountryISO2Func: func(countryISO2 string) ([]repository.SwiftCode, error) {
+		GetCountryFunc: func(iso2 string) (*repository.Country, error) {
+			return &repository.Country{ISO2: "PL", ISO3: "POL", Name: "POLAND"}, nil
+		},
+		CountByCountryISO2Func: func(q repository.CountryQuery) (int, error) {
+			return 2, nil
+		},
+		GetByCountryISO2Func: func(q repository.CountryQuery) ([]repository.SwiftCode, error) {
 			return []repository.SwiftCode{
 				{
 					SwiftCode:   "SWIFT1",
@@ -137,26 +255,191 @@ func TestGetSwiftCodesByCountry_Success(t *testing.T) {
 	}
 
 	svc := NewSwiftService(mockRepo)
-	result, err := svc.GetSwiftCodesByCountry("PL")
+	result, err := svc.GetSwiftCodesByCountry(context.Background(), "PL", CountryListOptions{})
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "PL", result.CountryISO2)
-	assert.Equal(t, "Poland", result.CountryName)
+	assert.Equal(t, "POLAND", result.CountryName)
 	assert.Len(t, result.SwiftCodes, 2)
+	assert.Equal(t, 2, result.TotalCount)
+	assert.Empty(t, result.NextCursor)
+}
+
+func TestGetSwiftCodesByCountry_Pagination(t *testing.T) {
+	page := []repository.SwiftCode{
+		{SwiftCode: "AAAAPLPWXXX", BankName: "A BANK", CountryISO2: "PL"},
+		{SwiftCode: "BBBBPLPWXXX", BankName: "B BANK", CountryISO2: "PL"},
+		{SwiftCode: "CCCCPLPWXXX", BankName: "C BANK", CountryISO2: "PL"},
+	}
+	var queries []repository.CountryQuery
+	mockRepo := &mockSwiftRepo{
+		GetCountryFunc: func(iso2 string) (*repository.Country, error) {
+			return &repository.Country{ISO2: "PL", ISO3: "POL", Name: "POLAND"}, nil
+		},
+		CountByCountryISO2Func: func(q repository.CountryQuery) (int, error) {
+			return len(page), nil
+		},
+		GetByCountryISO2Func: func(q repository.CountryQuery) ([]repository.SwiftCode, error) {
+			queries = append(queries, q)
+			var result []repository.SwiftCode
+			for _, sc := range page {
+				if sc.BankName > q.AfterSortValue && len(result) < q.Limit {
+					result = append(result, sc)
+				}
+			}
+			return result, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	first, err := svc.GetSwiftCodesByCountry(context.Background(), "pl", CountryListOptions{Limit: 2, Sort: "bankName"})
+	assert.NoError(t, err)
+	assert.Len(t, first.SwiftCodes, 2)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Equal(t, 3, queries[0].Limit)
+	assert.Equal(t, repository.SortByBankName, queries[0].SortBy)
+
+	second, err := svc.GetSwiftCodesByCountry(context.Background(), "PL", CountryListOptions{Limit: 2, Sort: "bankName", Cursor: first.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, "B BANK", queries[1].AfterSortValue)
+	assert.Equal(t, "BBBBPLPWXXX", queries[1].AfterSwiftCode)
+	assert.Len(t, second.SwiftCodes, 1)
+	assert.Equal(t, "CCCCPLPWXXX", second.SwiftCodes[0].SwiftCode)
+	assert.Empty(t, second.NextCursor)
+
+	_, err = svc.GetSwiftCodesByCountry(context.Background(), "PL", CountryListOptions{Cursor: first.NextCursor})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "cursor", validationErr.Fields[0].Field)
 }
 
 func TestGetSwiftCodesByCountry_NotFound(t *testing.T) {
 	mockRepo := &mockSwiftRepo{
-		GetByCountryISO2Func: func(countryISO2 string) ([]repository.SwiftCode, error) {
-			return []repository.SwiftCode{}, nil
+		GetCountryFunc: func(iso2 string) (*repository.Country, error) {
+			return &repository.Country{ISO2: "AD", ISO3: "AND", Name: "ANDORRA"}, nil
+		},
+		CountByCountryISO2Func: func(q repository.CountryQuery) (int, error) {
+			return 0, nil
 		},
 	}
 
 	svc := NewSwiftService(mockRepo)
-	result, err := svc.GetSwiftCodesByCountry("XX")
+	result, err := svc.GetSwiftCodesByCountry(context.Background(), "AD", CountryListOptions{})
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "no swift codes found")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetSwiftCodesByCountry_FilterMatchesNothing(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetCountryFunc: func(iso2 string) (*repository.Country, error) {
+			return &repository.Country{ISO2: "PL", ISO3: "POL", Name: "POLAND"}, nil
+		},
+		CountByCountryISO2Func: func(q repository.CountryQuery) (int, error) {
+			assert.Equal(t, "ZZZ", q.BankNamePrefix)
+			return 0, nil
+		},
+		GetByCountryISO2Func: func(q repository.CountryQuery) ([]repository.SwiftCode, error) {
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.GetSwiftCodesByCountry(context.Background(), "PL", CountryListOptions{BankNamePrefix: "ZZZ"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.TotalCount)
+	assert.NotNil(t, result.SwiftCodes)
+	assert.Empty(t, result.SwiftCodes)
+	assert.Empty(t, result.NextCursor)
+}
+
+func TestGetSwiftCodesByCountry_UnknownCountry(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetCountryFunc: func(iso2 string) (*repository.Country, error) {
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.GetSwiftCodesByCountry(context.Background(), "XX", CountryListOptions{})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = svc.GetSwiftCodesByCountry(context.Background(), "P1", CountryListOptions{})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "must consist of 2 letters", validationErr.Fields[0].Message)
+}
+
+func TestListCountries(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		ListCountriesFunc: func(includeEmpty bool) ([]repository.Country, error) {
+			assert.False(t, includeEmpty)
+			return []repository.Country{
+				{ISO2: "AL", ISO3: "ALB", Name: "ALBANIA", HeadquarterCount: 2, BranchCount: 5},
+				{ISO2: "PL", ISO3: "POL", Name: "POLAND", HeadquarterCount: 10, BranchCount: 30},
+			}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.ListCountries(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalCount)
+	assert.Equal(t, "ALB", result.Countries[0].CountryISO3)
+	assert.Equal(t, 7, result.Countries[0].TotalCount)
+	assert.Equal(t, 40, result.Countries[1].TotalCount)
+}
+
+func TestGetCountry(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetCountryFunc: func(iso2 string) (*repository.Country, error) {
+			if iso2 == "AD" {
+				return &repository.Country{ISO2: "AD", ISO3: "AND", Name: "ANDORRA"}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	country, err := svc.GetCountry(context.Background(), "ad")
+	assert.NoError(t, err)
+	assert.Equal(t, "ANDORRA", country.CountryName)
+	assert.Equal(t, 0, country.TotalCount)
+
+	_, err = svc.GetCountry(context.Background(), "XX")
+	assert.ErrorIs(t, err, ErrUnknownCountry)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSearchSwiftCodes_Success(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		SearchFunc: func(q repository.SearchQuery) ([]repository.SearchResult, int, error) {
+			assert.Equal(t, "pko bp warszawa", q.Text)
+			assert.Equal(t, "PL", q.CountryISO2)
+			assert.Equal(t, DefaultSearchLimit, q.Limit)
+			return []repository.SearchResult{
+				{SwiftCode: repository.SwiftCode{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BANK POLSKI", IsHeadquarter: true}, Score: 0.9},
+			}, 1, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.SearchSwiftCodes(context.Background(), SearchOptions{Query: " pko bp warszawa ", CountryISO2: "pl"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.TotalCount)
+	assert.Len(t, result.Results, 1)
+	assert.Equal(t, "BPKOPLPWXXX", result.Results[0].SwiftCode)
+	assert.Equal(t, 0.9, result.Results[0].Score)
+}
+
+func TestSearchSwiftCodes_InvalidQuery(t *testing.T) {
+	svc := NewSwiftService(&mockSwiftRepo{})
+	_, err := svc.SearchSwiftCodes(context.Background(), SearchOptions{Query: "p", Limit: MaxSearchLimit + 1})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Fields, 2)
 }
 
 func TestCreateSwiftCode_Success(t *testing.T) {
@@ -167,29 +450,371 @@ func TestCreateSwiftCode_Success(t *testing.T) {
 	}
 
 	svc := NewSwiftService(mockRepo)
+	isHeadquarter := true
 	input := CreateSwiftCodeInput{
-		SwiftCode:     "NEWSWIFT",
+		SwiftCode:     "NEWSPLPWXXX",
 		BankName:      "Test Bank",
 		Address:       "Test Address",
 		CountryISO2:   "PL",
 		CountryName:   "Poland",
-		IsHeadquarter: true,
+		IsHeadquarter: &isHeadquarter,
+	}
+	err := svc.CreateSwiftCode(context.Background(), input)
+	assert.NoError(t, err)
+}
+
+func TestCreateSwiftCode_DerivesHeadquarter(t *testing.T) {
+	var created []repository.SwiftCode
+	mockRepo := &mockSwiftRepo{
+		CreateSwiftCodeFunc: func(swift repository.SwiftCode) error {
+			created = append(created, swift)
+			return nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	assert.NoError(t, svc.CreateSwiftCode(context.Background(), CreateSwiftCodeInput{
+		SwiftCode:   "bpkoplpw",
+		BankName:    "PKO BP",
+		CountryISO2: "pl",
+		CountryName: "Poland",
+	}))
+	assert.NoError(t, svc.CreateSwiftCode(context.Background(), CreateSwiftCodeInput{
+		SwiftCode:   "BPKOPLPW123",
+		BankName:    "PKO BP",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+	}))
+
+	assert.Len(t, created, 2)
+	assert.Equal(t, "BPKOPLPWXXX", created[0].SwiftCode)
+	assert.Equal(t, DefaultCodeType, created[0].CodeType)
+	assert.True(t, created[0].IsHeadquarter)
+	assert.False(t, created[0].HeadquarterSwiftCode.Valid)
+	assert.False(t, created[1].IsHeadquarter)
+	assert.Equal(t, sql.NullString{String: "BPKOPLPWXXX", Valid: true}, created[1].HeadquarterSwiftCode)
+}
+
+func TestCreateSwiftCode_InconsistentHeadquarter(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		CreateSwiftCodeFunc: func(swift repository.SwiftCode) error {
+			t.Fatal("repository should not be called for inconsistent input")
+			return nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	isHeadquarter := true
+	wrongHQ := "BPKOPLPKXXX"
+	err := svc.CreateSwiftCode(context.Background(), CreateSwiftCodeInput{
+		SwiftCode:            "BPKOPLPW123",
+		BankName:             "PKO BP",
+		CountryISO2:          "PL",
+		CountryName:          "Poland",
+		IsHeadquarter:        &isHeadquarter,
+		HeadquarterSwiftCode: &wrongHQ,
+	})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Fields, 2)
+	assert.Equal(t, "isHeadquarter", validationErr.Fields[0].Field)
+	assert.Equal(t, "headquarterSwiftCode", validationErr.Fields[1].Field)
+}
+
+func TestCreateSwiftCode_AlreadyExists(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		CreateSwiftCodeFunc: func(swift repository.SwiftCode) error {
+			return repository.ErrDuplicateSwiftCode
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	err := svc.CreateSwiftCode(context.Background(), CreateSwiftCodeInput{
+		SwiftCode:   "BPKOPLPWXXX",
+		BankName:    "PKO BP",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+	})
+	assert.ErrorIs(t, err, ErrSwiftCodeExists)
+}
+
+func TestReplaceSwiftCode_Updated(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		UpsertSwiftCodeFunc: func(swift repository.SwiftCode) (bool, []repository.FieldChange, error) {
+			assert.Equal(t, "BPKOPLPWXXX", swift.SwiftCode)
+			return false, []repository.FieldChange{{Field: "bankName", OldValue: "PKO", NewValue: "PKO BP"}}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.ReplaceSwiftCode(context.Background(), "BPKOPLPWXXX", CreateSwiftCodeInput{
+		BankName:    "PKO BP",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Created)
+	assert.Len(t, result.Changes, 1)
+	assert.Equal(t, "bankName", result.Changes[0].Field)
+}
+
+func TestReplaceSwiftCode_DuplicateIsConflict(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		UpsertSwiftCodeFunc: func(swift repository.SwiftCode) (bool, []repository.FieldChange, error) {
+			return false, nil, repository.ErrDuplicateSwiftCode
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.ReplaceSwiftCode(context.Background(), "BPKOPLPWXXX", CreateSwiftCodeInput{
+		BankName:    "PKO BP",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestReplaceSwiftCode_CodeMismatch(t *testing.T) {
+	svc := NewSwiftService(&mockSwiftRepo{})
+	_, err := svc.ReplaceSwiftCode(context.Background(), "BPKOPLPWXXX", CreateSwiftCodeInput{
+		SwiftCode:   "PKOPPLPWXXX",
+		BankName:    "PKO BP",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+	})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "swiftCode", validationErr.Fields[0].Field)
+}
+
+func TestUpdateSwiftCode_OnlySuppliedFields(t *testing.T) {
+	stored := repository.SwiftCode{
+		SwiftCode:            "BPKOPLPW123",
+		BankName:             "PKO BP",
+		Address:              "Old Address",
+		CountryISO2:          "PL",
+		CountryName:          "POLAND",
+		HeadquarterSwiftCode: sql.NullString{String: "BPKOPLPWXXX", Valid: true},
+	}
+	inTx := false
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodeFunc: func(code string) (*repository.SwiftCode, error) {
+			swift := stored
+			return &swift, nil
+		},
+		GetBySwiftCodeForUpdateFunc: func(code string) (*repository.SwiftCode, error) {
+			// Odczyt do scalenia musi być w transakcji zapisu, inaczej równoległy PATCH zginie.
+			assert.True(t, inTx)
+			swift := stored
+			return &swift, nil
+		},
+		UpdateSwiftCodeFunc: func(code string, update repository.SwiftCodeUpdate) error {
+			assert.Equal(t, "BPKOPLPW123", code)
+			assert.Nil(t, update.BankName)
+			assert.Nil(t, update.CountryName)
+			assert.Nil(t, update.HeadquarterSwiftCode)
+			assert.Equal(t, "New Address", *update.Address)
+			stored.Address = *update.Address
+			return nil
+		},
+	}
+
+	mockRepo.WithTxFunc = func(fn func(repo repository.SwiftRepository) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(mockRepo)
 	}
-	err := svc.CreateSwiftCode(input)
+
+	svc := NewSwiftService(mockRepo)
+	address := "New Address"
+	result, err := svc.UpdateSwiftCode(context.Background(), "BPKOPLPW123", UpdateSwiftCodeInput{Address: &address})
+	assert.NoError(t, err)
+
+	branchResp, ok := result.(*SwiftCodeResponseBR)
+	assert.True(t, ok)
+	assert.Equal(t, "New Address", branchResp.Address)
+}
+
+func TestReplaceSwiftCode_KeepsSpreadsheetColumns(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		UpsertSwiftCodeFunc: func(swift repository.SwiftCode) (bool, []repository.FieldChange, error) {
+			assert.Equal(t, "BIC11", swift.CodeType)
+			assert.Equal(t, "PULAWSKA 15", swift.Address)
+			assert.Equal(t, "WARSZAWA", swift.TownName)
+			assert.Equal(t, "Europe/Warsaw", swift.TimeZone)
+			return true, nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.ReplaceSwiftCode(context.Background(), "BPKOPLPWXXX", CreateSwiftCodeInput{
+		CodeType:    "bic11",
+		BankName:    "PKO BP",
+		Address:     "PULAWSKA 15",
+		TownName:    " WARSZAWA ",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+		TimeZone:    "Europe/Warsaw",
+	})
 	assert.NoError(t, err)
+	assert.True(t, result.Created)
+}
+
+func TestUpdateSwiftCode_InvalidResult(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodeForUpdateFunc: func(code string) (*repository.SwiftCode, error) {
+			return &repository.SwiftCode{
+				SwiftCode:     "BPKOPLPWXXX",
+				BankName:      "PKO BP",
+				CountryISO2:   "PL",
+				CountryName:   "POLAND",
+				IsHeadquarter: true,
+			}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	empty := ""
+	countryISO2 := "DE"
+	_, err := svc.UpdateSwiftCode(context.Background(), "BPKOPLPWXXX", UpdateSwiftCodeInput{BankName: &empty, CountryISO2: &countryISO2})
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "bankName", validationErr.Fields[0].Field)
+	assert.Equal(t, "swiftCode", validationErr.Fields[1].Field)
+}
+
+func TestUpdateSwiftCode_NotFound(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodeForUpdateFunc: func(code string) (*repository.SwiftCode, error) {
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	address := "New Address"
+	_, err := svc.UpdateSwiftCode(context.Background(), "BPKOPLPWXXX", UpdateSwiftCodeInput{Address: &address})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, ErrSwiftCodeNotFound)
+}
+
+func TestCreateSwiftCode_InvalidBIC(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		CreateSwiftCodeFunc: func(swift repository.SwiftCode) error {
+			t.Fatal("repository should not be called for invalid input")
+			return nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	err := svc.CreateSwiftCode(context.Background(), CreateSwiftCodeInput{
+		SwiftCode:   "TESTCODE123",
+		BankName:    "Test Bank",
+		CountryISO2: "PL",
+		CountryName: "Poland",
+	})
+	assert.Error(t, err)
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "swiftCode", validationErr.Fields[0].Field)
+	assert.Contains(t, validationErr.Fields[0].Message, "does not match countryISO2")
+}
+
+func TestValidateBIC(t *testing.T) {
+	tests := []struct {
+		code    string
+		country string
+		valid   bool
+	}{
+		{"BPKOPLPW", "PL", true},
+		{"BPKOPLPWXXX", "PL", true},
+		{"BPKOPLPW123", "PL", true},
+		{"BPKOPLP1", "", true},
+		{"BPKOPLPWXX", "PL", false},
+		{"BPK1PLPWXXX", "PL", false},
+		{"BPKODEPWXXX", "PL", false},
+		{"BPKOPLP-XXX", "PL", false},
+		{"BPKOPLPWX12", "PL", false},
+		{"bpkoplpwxxx", "PL", false},
+	}
+
+	for _, tt := range tests {
+		fieldErrs := ValidateBIC(tt.code, tt.country)
+		if tt.valid {
+			assert.Empty(t, fieldErrs, tt.code)
+		} else {
+			assert.NotEmpty(t, fieldErrs, tt.code)
+		}
+	}
 }
 
 func TestDeleteSwiftCode_Success(t *testing.T) {
 	mockRepo := &mockSwiftRepo{
+		CountBranchesFunc: func(hqCode string) (int, error) {
+			return 0, nil
+		},
 		DeleteBySwiftCodeFunc: func(code string) error {
 			return nil
 		},
 	}
 	svc := NewSwiftService(mockRepo)
-	err := svc.DeleteSwiftCode("NEWSWIFT")
+	_, err := svc.DeleteSwiftCode(context.Background(), "NEWSWIFT", "")
 	assert.NoError(t, err)
 }
 
+func TestDeleteSwiftCode_RestrictWithBranches(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		CountBranchesFunc: func(hqCode string) (int, error) {
+			assert.Equal(t, "BPKOPLPWXXX", hqCode)
+			return 2, nil
+		},
+		DeleteBySwiftCodeFunc: func(code string) error {
+			t.Fatal("headquarter with branches must not be deleted")
+			return nil
+		},
+	}
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.DeleteSwiftCode(context.Background(), "BPKOPLPWXXX", BranchPolicyRestrict)
+	assert.ErrorIs(t, err, ErrSwiftCodeHasBranches)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestDeleteSwiftCode_BranchPolicies(t *testing.T) {
+	var deleted []string
+	mockRepo := &mockSwiftRepo{
+		DeleteBranchesFunc: func(hqCode string) (int, error) {
+			return 3, nil
+		},
+		DetachBranchesFunc: func(hqCode string) (int, error) {
+			return 2, nil
+		},
+		DeleteBySwiftCodeFunc: func(code string) error {
+			deleted = append(deleted, code)
+			return nil
+		},
+	}
+	svc := NewSwiftService(mockRepo)
+
+	result, err := svc.DeleteSwiftCode(context.Background(), "BPKOPLPWXXX", BranchPolicyCascade)
+	assert.NoError(t, err)
+	assert.Equal(t, &DeleteResult{DeletedBranches: 3}, result)
+
+	result, err = svc.DeleteSwiftCode(context.Background(), "BPKOPLPWXXX", BranchPolicyDetach)
+	assert.NoError(t, err)
+	assert.Equal(t, &DeleteResult{DetachedBranches: 2}, result)
+
+	// Oddział nie ma własnych oddziałów - polityka nie ma znaczenia.
+	_, err = svc.DeleteSwiftCode(context.Background(), "BPKOPLPW123", BranchPolicyRestrict)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BPKOPLPWXXX", "BPKOPLPWXXX", "BPKOPLPW123"}, deleted)
+
+	_, err = svc.DeleteSwiftCode(context.Background(), "BPKOPLPWXXX", "everything")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
 func TestDeleteSwiftCode_NotFound(t *testing.T) {
 	mockRepo := &mockSwiftRepo{
 		DeleteBySwiftCodeFunc: func(code string) error {
@@ -197,7 +822,603 @@ func TestDeleteSwiftCode_NotFound(t *testing.T) {
 		},
 	}
 	svc := NewSwiftService(mockRepo)
-	err := svc.DeleteSwiftCode("UNKNOWN")
+	_, err := svc.DeleteSwiftCode(context.Background(), "UNKNOWN123", "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
+
+func TestImportSwiftCodes_Success(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		ImportSwiftCodesFunc: func(codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error) {
+			assert.Len(t, codes, 2)
+			assert.Equal(t, sql.NullString{String: "BPKOPLPWXXX", Valid: true}, codes[1].HeadquarterSwiftCode)
+			return repository.ImportResult{Inserted: 1, Updated: 1}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	report, err := svc.ImportSwiftCodes(context.Background(), []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 3, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPW123", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+	}, ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.TotalRows)
+	assert.Equal(t, 1, report.Inserted)
+	assert.Equal(t, 1, report.Updated)
+	assert.Empty(t, report.Rejected)
+}
+
+func TestImportSwiftCodes_SyncMode(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		ImportSwiftCodesFunc: func(codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error) {
+			assert.True(t, opts.DeleteMissing)
+			assert.Equal(t, 5.0, opts.MaxDeletePercent)
+			return repository.ImportResult{}, fmt.Errorf("%w: import would delete 10 of 20 swift codes", repository.ErrDeleteThresholdExceeded)
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	records := []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+	}
+	_, err := svc.ImportSwiftCodes(context.Background(), records, ImportOptions{Mode: ImportModeSync, MaxDeletePercent: 5})
+	assert.ErrorIs(t, err, ErrDeleteThresholdExceeded)
+
+	_, err = svc.ImportSwiftCodes(context.Background(), records, ImportOptions{Mode: "replace-all"})
+	assert.Error(t, err)
+}
+
+func TestImportSwiftCodes_DeleteAction(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		ImportSwiftCodesFunc: func(codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error) {
+			assert.Len(t, codes, 1)
+			assert.Equal(t, "BIC", codes[0].InstitutionType)
+			assert.Equal(t, []string{"BPKOPLPWXXX"}, opts.DeleteCodes)
+			return repository.ImportResult{Inserted: 1, Deleted: 1, Detached: 1}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	report, err := svc.ImportSwiftCodes(context.Background(), []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPW123", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND", InstitutionType: "bic"}},
+		{Row: 3, Action: ImportActionDelete, Input: CreateSwiftCodeInput{SwiftCode: "bpkoplpw"}},
+	}, ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Deleted)
+	assert.Equal(t, 1, report.Detached)
+
+	report, err = svc.ImportSwiftCodes(context.Background(), []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 3, Action: ImportActionDelete, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPWXXX"}},
+		{Row: 4, Action: ImportActionDelete, Input: CreateSwiftCodeInput{SwiftCode: "BPKO"}},
+	}, ImportOptions{})
+	assert.ErrorIs(t, err, ErrImportRejected)
+	assert.Len(t, report.Rejected, 2)
+	assert.Contains(t, report.Rejected[0].Errors[0].Message, "duplicate of row 2")
+	assert.Equal(t, 4, report.Rejected[1].Row)
+}
+
+func TestLookupSwiftCodes(t *testing.T) {
+	calls := 0
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodesFunc: func(codes []string) ([]repository.SwiftCode, error) {
+			calls++
+			assert.Equal(t, []string{"BPKOPLPW123", "BPKOPLPWXXX", "PKOPPLPWXXX"}, codes)
+			return []repository.SwiftCode{
+				{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", IsHeadquarter: true},
+				{SwiftCode: "BPKOPLPW123", BankName: "PKO BP"},
+			}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.LookupSwiftCodes(context.Background(), []string{"BPKOPLPW123", "bpkoplpw", "PKOPPLPWXXX", "BPKOPLPWXXX"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Len(t, result.Found, 2)
+	assert.Equal(t, "BPKOPLPW123", result.Found[0].SwiftCode)
+	assert.Equal(t, "BPKOPLPWXXX", result.Found[1].SwiftCode)
+	assert.Equal(t, []string{"PKOPPLPWXXX"}, result.NotFound)
+
+	_, err = svc.LookupSwiftCodes(context.Background(), nil)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = svc.LookupSwiftCodes(context.Background(), make([]string, MaxLookupCodes+1))
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExportSwiftCodes(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		StreamSwiftCodesFunc: func(q repository.ExportQuery, fn func(repository.SwiftCode) error) error {
+			assert.Equal(t, "PL", q.CountryISO2)
+			for _, code := range []string{"BPKOPLPWXXX", "BPKOPLPW123"} {
+				if err := fn(repository.SwiftCode{SwiftCode: code}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	var exported []string
+	err := svc.ExportSwiftCodes(context.Background(), ExportOptions{CountryISO2: " pl"}, func(swift repository.SwiftCode) error {
+		exported = append(exported, swift.SwiftCode)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BPKOPLPWXXX", "BPKOPLPW123"}, exported)
+
+	err = svc.ExportSwiftCodes(context.Background(), ExportOptions{CountryISO2: "POL"}, func(repository.SwiftCode) error { return nil })
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestImportSwiftCodes_RejectsWholeFile(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		ImportSwiftCodesFunc: func(codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error) {
+			t.Fatal("repository should not be called when rows are rejected")
+			return repository.ImportResult{}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	report, err := svc.ImportSwiftCodes(context.Background(), []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 3, Input: CreateSwiftCodeInput{SwiftCode: "BPKODEPW123", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 4, Input: CreateSwiftCodeInput{SwiftCode: "bpkoplpw", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"}},
+	}, ImportOptions{})
+	assert.ErrorIs(t, err, ErrImportRejected)
+	assert.Len(t, report.Rejected, 2)
+	assert.Equal(t, 3, report.Rejected[0].Row)
+	assert.Equal(t, "swiftCode", report.Rejected[0].Errors[0].Field)
+	assert.Equal(t, 4, report.Rejected[1].Row)
+	assert.Contains(t, report.Rejected[1].Errors[0].Message, "duplicate of row 2")
+}
+
+func TestDiffImport(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetAllFunc: func() ([]repository.SwiftCode, error) {
+			return []repository.SwiftCode{
+				{SwiftCode: "AAAAPLPWXXX", BankName: "A BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+				{SwiftCode: "BBBBPLPWXXX", BankName: "B BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+				{SwiftCode: "CCCCPLPWXXX", BankName: "C BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+				{SwiftCode: "FFFFPLPWXXX", BankName: "F BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+			}, nil
+		},
+		ImportSwiftCodesFunc: func(codes []repository.SwiftCode, opts repository.ImportOptions) (repository.ImportResult, error) {
+			t.Fatal("dry run must not write to the repository")
+			return repository.ImportResult{}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	diff, err := svc.DiffImport(context.Background(), []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "AAAAPLPWXXX", BankName: "A BANK", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 3, Input: CreateSwiftCodeInput{SwiftCode: "BBBBPLPWXXX", BankName: "B BANK S.A.", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 4, Input: CreateSwiftCodeInput{SwiftCode: "DDDDPLPWXXX", BankName: "D BANK", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 5, Input: CreateSwiftCodeInput{SwiftCode: "EEEEDEFFXXX", BankName: "E BANK", CountryISO2: "PL", CountryName: "POLAND"}},
+		// Odrzucony wiersz istniejącego kodu nie oznacza jego usunięcia.
+		{Row: 6, Input: CreateSwiftCodeInput{SwiftCode: "ffffplpw", CountryISO2: "PL", CountryName: "POLAND"}},
+	}, ImportModeSync)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, diff.TotalRows)
+	assert.Equal(t, 1, diff.Unchanged)
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, "DDDDPLPWXXX", diff.Added[0].SwiftCode)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "CCCCPLPWXXX", diff.Removed[0].SwiftCode)
+	assert.Len(t, diff.Modified, 1)
+	assert.Equal(t, []repository.FieldChange{{Field: "bankName", OldValue: "B BANK", NewValue: "B BANK S.A."}}, diff.Modified[0].Changes)
+	assert.Len(t, diff.Rejected, 2)
+	assert.Equal(t, 5, diff.Rejected[0].Row)
+	assert.Equal(t, 6, diff.Rejected[1].Row)
+}
+
+func TestDiffImport_DeltaFile(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetAllFunc: func() ([]repository.SwiftCode, error) {
+			return []repository.SwiftCode{
+				{SwiftCode: "AAAAPLPWXXX", BankName: "A BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+				{SwiftCode: "BBBBPLPWXXX", BankName: "B BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+				{SwiftCode: "CCCCPLPWXXX", BankName: "C BANK", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true, CodeType: "BIC11"},
+			}, nil
+		},
+	}
+	records := []ImportRecord{
+		{Row: 2, Input: CreateSwiftCodeInput{SwiftCode: "AAAAPLPWXXX", BankName: "A BANK S.A.", CountryISO2: "PL", CountryName: "POLAND"}},
+		{Row: 3, Action: ImportActionDelete, Input: CreateSwiftCodeInput{SwiftCode: "BBBBPLPWXXX"}},
+		{Row: 4, Action: ImportActionDelete, Input: CreateSwiftCodeInput{SwiftCode: "DDDDPLPWXXX"}},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	diff, err := svc.DiffImport(context.Background(), records, ImportModeUpsert)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Modified, 1)
+	// Kod spoza pliku zostaje - usuwa go tylko sync; nieznany kod z flagą D nic nie zmienia.
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "BBBBPLPWXXX", diff.Removed[0].SwiftCode)
+
+	diff, err = svc.DiffImport(context.Background(), records, ImportModeSync)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Removed, 2)
+
+	_, err = svc.DiffImport(context.Background(), records, "mirror")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestBulkCreateSwiftCodes_AtomicRollsBack(t *testing.T) {
+	var created []string
+	txCalls := 0
+	mockRepo := &mockSwiftRepo{
+		CreateSwiftCodeFunc: func(swift repository.SwiftCode) error {
+			if swift.SwiftCode == "BPKOPLPW123" {
+				return repository.ErrDuplicateSwiftCode
+			}
+			created = append(created, swift.SwiftCode)
+			return nil
+		},
+	}
+	mockRepo.WithTxFunc = func(fn func(repo repository.SwiftRepository) error) error {
+		txCalls++
+		return fn(mockRepo)
+	}
+
+	svc := NewSwiftService(mockRepo)
+	results, err := svc.BulkCreateSwiftCodes(context.Background(), []CreateSwiftCodeInput{
+		{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+		{SwiftCode: "BPKOPLPW123", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+		{SwiftCode: "BPKOPLPW456", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+	}, BulkModeAtomic)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, txCalls)
+	assert.Equal(t, []string{"BPKOPLPWXXX"}, created)
+	assert.ErrorIs(t, results[0].Err, ErrBulkRolledBack)
+	assert.ErrorIs(t, results[1].Err, ErrSwiftCodeExists)
+	assert.ErrorIs(t, results[2].Err, ErrBulkRolledBack)
+}
+
+func TestBulkCreateSwiftCodes_AtomicValidationSkipsDatabase(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		WithTxFunc: func(fn func(repo repository.SwiftRepository) error) error {
+			t.Fatal("transaction should not start when an item is invalid")
+			return nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	results, err := svc.BulkCreateSwiftCodes(context.Background(), []CreateSwiftCodeInput{
+		{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+		{SwiftCode: "bpkoplpw", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+	}, BulkModeAtomic)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, ErrBulkRolledBack)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, results[1].Err, &validationErr)
+	assert.Contains(t, validationErr.Fields[0].Message, "duplicate of item 0")
+
+	_, err = svc.BulkCreateSwiftCodes(context.Background(), nil, BulkModeAtomic)
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestBulkDeleteSwiftCodes_BestEffort(t *testing.T) {
+	transactions := 0
+	mockRepo := &mockSwiftRepo{
+		CountBranchesFunc: func(hqCode string) (int, error) {
+			return 0, nil
+		},
+		DeleteBySwiftCodeFunc: func(code string) error {
+			if code == "BPKOPLPW123" {
+				return sql.ErrNoRows
+			}
+			return nil
+		},
+	}
+	mockRepo.WithTxFunc = func(fn func(repo repository.SwiftRepository) error) error {
+		transactions++
+		return fn(mockRepo)
+	}
+
+	svc := NewSwiftService(mockRepo)
+	results, err := svc.BulkDeleteSwiftCodes(context.Background(), []string{"bpkoplpw", "BPKOPLPW123", "BPKO"}, BulkModeBestEffort, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, transactions, "each valid item runs in its own transaction")
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "BPKOPLPWXXX", results[0].SwiftCode)
+	assert.ErrorIs(t, results[1].Err, ErrSwiftCodeNotFound)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, results[2].Err, &validationErr)
+
+	_, err = svc.BulkDeleteSwiftCodes(context.Background(), []string{"BPKOPLPWXXX"}, "sometimes", "")
+	assert.Error(t, err)
+}
+
+func TestGetBank(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetByInstitutionCodeFunc: func(institutionCode string) ([]repository.SwiftCode, error) {
+			assert.Equal(t, "BPKO", institutionCode)
+			return []repository.SwiftCode{
+				{SwiftCode: "BPKODEFF123", BankName: "PKO BP DE", CountryISO2: "DE", CountryName: "GERMANY"},
+				{SwiftCode: "BPKOPLP2XXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true},
+				{SwiftCode: "BPKOPLPW123", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+				{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true},
+				{SwiftCode: "BPKOPLPWXYZ", BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"},
+			}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	bank, err := svc.GetBank(context.Background(), "bpko")
+	assert.NoError(t, err)
+	assert.Equal(t, "BPKO", bank.InstitutionCode)
+	assert.Equal(t, []string{"PKO BP", "PKO BP DE"}, bank.BankNames)
+	assert.Equal(t, 5, bank.TotalCount)
+	assert.Equal(t, 2, bank.HeadquarterCount)
+	assert.Equal(t, 3, bank.BranchCount)
+
+	assert.Len(t, bank.Countries, 2)
+	de := bank.Countries[0]
+	assert.Equal(t, "DE", de.CountryISO2)
+	assert.Len(t, de.Locations, 1)
+	assert.Nil(t, de.Locations[0].Headquarter)
+	assert.Equal(t, 1, de.Locations[0].BranchCount)
+
+	pl := bank.Countries[1]
+	assert.Equal(t, 2, pl.HeadquarterCount)
+	assert.Equal(t, 2, pl.BranchCount)
+	assert.Len(t, pl.Locations, 2)
+	assert.Equal(t, "P2", pl.Locations[0].LocationCode)
+	assert.Empty(t, pl.Locations[0].Branches)
+	assert.Equal(t, "PW", pl.Locations[1].LocationCode)
+	assert.Equal(t, "BPKOPLPW", pl.Locations[1].BIC8)
+	assert.Equal(t, "BPKOPLPWXXX", pl.Locations[1].Headquarter.SwiftCode)
+	assert.Len(t, pl.Locations[1].Branches, 2)
+}
+
+func TestGetBank_Errors(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetByInstitutionCodeFunc: func(institutionCode string) ([]repository.SwiftCode, error) {
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.GetBank(context.Background(), "AAAA")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.GetBank(context.Background(), "BPK1")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestGetSwiftCodeHistory(t *testing.T) {
+	created := repository.SwiftCode{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", IsHeadquarter: true}
+	renamed := created
+	renamed.BankName = "PKO BANK POLSKI"
+	changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockRepo := &mockSwiftRepo{
+		GetHistoryFunc: func(code string) ([]repository.HistoryEntry, error) {
+			assert.Equal(t, "BPKOPLPWXXX", code)
+			return []repository.HistoryEntry{
+				{Operation: "INSERT", ChangedAt: changedAt, Actor: "system", Source: "import", New: &created},
+				{Operation: "UPDATE", ChangedAt: changedAt.Add(time.Hour), Actor: "jan", Source: "api", Old: &created, New: &renamed},
+				{Operation: "DELETE", ChangedAt: changedAt.Add(2 * time.Hour), Actor: "jan", Source: "api", Old: &renamed},
+			}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.GetSwiftCodeHistory(repository.WithDeleted(context.Background()), "bpkoplpw")
+	assert.NoError(t, err)
+	assert.Equal(t, "BPKOPLPWXXX", result.SwiftCode)
+	assert.Len(t, result.History, 3)
+
+	assert.Nil(t, result.History[0].OldValues)
+	assert.Empty(t, result.History[0].Changes)
+	assert.Equal(t, []repository.FieldChange{{Field: "bankName", OldValue: "PKO BP", NewValue: "PKO BANK POLSKI"}}, result.History[1].Changes)
+	assert.Equal(t, "jan", result.History[1].Actor)
+	assert.Nil(t, result.History[2].NewValues)
+	assert.Equal(t, "PKO BANK POLSKI", result.History[2].OldValues.BankName)
+}
+
+func TestGetSwiftCodeHistory_NotFound(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetHistoryFunc: func(code string) ([]repository.HistoryEntry, error) {
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.GetSwiftCodeHistory(repository.WithDeleted(context.Background()), "AAAAPLPWXXX")
+	assert.ErrorIs(t, err, ErrSwiftCodeNotFound)
+}
+
+func TestGetSwiftCodeHistory_PublicHidesDeletion(t *testing.T) {
+	created := repository.SwiftCode{SwiftCode: "BPKOPLPWXXX", BankName: "PKO BP", CountryISO2: "PL", IsHeadquarter: true}
+	deleted := created
+	deleted.DeletedAt = sql.NullTime{Time: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Valid: true}
+	deleted.DeletedBy = sql.NullString{String: "jan", Valid: true}
+	renamed := created
+	renamed.BankName = "PKO BANK POLSKI"
+	changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodeFunc: func(code string) (*repository.SwiftCode, error) {
+			return &renamed, nil
+		},
+		GetHistoryFunc: func(code string) ([]repository.HistoryEntry, error) {
+			return []repository.HistoryEntry{
+				{Operation: "INSERT", ChangedAt: changedAt, Actor: "system", Source: "import", New: &created},
+				{Operation: "UPDATE", ChangedAt: changedAt.Add(time.Hour), Actor: "jan", Source: "api", Old: &created, New: &deleted},
+				{Operation: "UPDATE", ChangedAt: changedAt.Add(2 * time.Hour), Actor: "anna", Source: "api", Old: &deleted, New: &created},
+				{Operation: "UPDATE", ChangedAt: changedAt.Add(3 * time.Hour), Actor: "anna", Source: "api", Old: &created, New: &renamed},
+			}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.GetSwiftCodeHistory(context.Background(), "BPKOPLPWXXX")
+	assert.NoError(t, err)
+	assert.Len(t, result.History, 2)
+	assert.Equal(t, "INSERT", result.History[0].Operation)
+	assert.Equal(t, []repository.FieldChange{{Field: "bankName", OldValue: "PKO BP", NewValue: "PKO BANK POLSKI"}}, result.History[1].Changes)
+	for _, entry := range result.History {
+		for _, values := range []*SwiftCodeResponseBR{entry.OldValues, entry.NewValues} {
+			if values != nil {
+				assert.Nil(t, values.DeletedAt)
+				assert.Empty(t, values.DeletedBy)
+			}
+		}
+	}
+}
+
+func TestGetSwiftCodeHistory_PublicDeletedCode(t *testing.T) {
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodeFunc: func(code string) (*repository.SwiftCode, error) {
+			return nil, nil
+		},
+		GetHistoryFunc: func(code string) ([]repository.HistoryEntry, error) {
+			t.Fatal("history of a deleted code must not be read on the public route")
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.GetSwiftCodeHistory(context.Background(), "BPKOPLPWXXX")
+	assert.ErrorIs(t, err, ErrSwiftCodeNotFound)
+}
+
+func TestGetSwiftCodeAsOf(t *testing.T) {
+	asOf := time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC)
+	mockRepo := &mockSwiftRepo{
+		GetBySwiftCodeAsOfFunc: func(code string, at time.Time) (*repository.SwiftCode, error) {
+			assert.Equal(t, asOf, at)
+			if code != "BPKOPLPWXXX" {
+				return nil, nil
+			}
+			return &repository.SwiftCode{SwiftCode: code, BankName: "PKO BP", IsHeadquarter: true}, nil
+		},
+		GetBranchesAsOfFunc: func(hqCode string, at time.Time) ([]repository.SwiftCode, error) {
+			assert.Equal(t, "BPKOPLPWXXX", hqCode)
+			return []repository.SwiftCode{{SwiftCode: "BPKOPLPW123", BankName: "PKO BP"}}, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.GetSwiftCodeAsOf(context.Background(), "BPKOPLPWXXX", asOf)
+	assert.NoError(t, err)
+	hq, ok := result.(*SwiftCodeResponseHQ)
+	assert.True(t, ok)
+	assert.Len(t, hq.Branches, 1)
+
+	_, err = svc.GetSwiftCodeAsOf(context.Background(), "AAAAPLPWXXX", asOf)
+	assert.ErrorIs(t, err, ErrSwiftCodeNotFound)
+}
+
+func TestRestoreSwiftCode(t *testing.T) {
+	var restored []string
+	var links []sql.NullString
+	mockRepo := &mockSwiftRepo{
+		UpdateSwiftCodeFunc: func(code string, update repository.SwiftCodeUpdate) error {
+			assert.Equal(t, "BPKOPLPW123", code)
+			links = append(links, *update.HeadquarterSwiftCode)
+			return nil
+		},
+		RestoreSwiftCodeFunc: func(code string) error {
+			if code != "BPKOPLPW123" {
+				return sql.ErrNoRows
+			}
+			restored = append(restored, code)
+			return nil
+		},
+		GetBySwiftCodeFunc: func(code string) (*repository.SwiftCode, error) {
+			switch code {
+			case "BPKOPLPW123":
+				return &repository.SwiftCode{SwiftCode: code, BankName: "PKO BP"}, nil
+			case "AAAAPLPWXXX":
+				return &repository.SwiftCode{SwiftCode: code, BankName: "ALIOR", IsHeadquarter: true}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	result, err := svc.RestoreSwiftCode(context.Background(), "bpkoplpw123")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BPKOPLPW123"}, restored)
+	br, ok := result.(*SwiftCodeResponseBR)
+	assert.True(t, ok)
+	assert.Nil(t, br.DeletedAt)
+	// Centrala BPKOPLPWXXX jest nadal usunięta, więc oddział wraca bez centrali.
+	assert.Equal(t, []sql.NullString{{}}, links)
+
+	// Aktywnego kodu nie da się przywrócić.
+	_, err = svc.RestoreSwiftCode(context.Background(), "AAAAPLPWXXX")
+	assert.ErrorIs(t, err, ErrSwiftCodeNotDeleted)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	_, err = svc.RestoreSwiftCode(context.Background(), "BBBBPLPWXXX")
+	assert.ErrorIs(t, err, ErrSwiftCodeNotFound)
+}
+
+func TestRestoreSwiftCode_BranchOfActiveHeadquarter(t *testing.T) {
+	var link *sql.NullString
+	mockRepo := &mockSwiftRepo{
+		RestoreSwiftCodeFunc: func(code string) error {
+			return nil
+		},
+		GetBySwiftCodeFunc: func(code string) (*repository.SwiftCode, error) {
+			return &repository.SwiftCode{SwiftCode: code, BankName: "PKO BP", IsHeadquarter: isHeadquarterCode(code)}, nil
+		},
+		GetBranchesByHeadquarterCodeFunc: func(hqCode string) ([]repository.SwiftCode, error) {
+			return nil, nil
+		},
+		UpdateSwiftCodeFunc: func(code string, update repository.SwiftCodeUpdate) error {
+			link = update.HeadquarterSwiftCode
+			return nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	_, err := svc.RestoreSwiftCode(context.Background(), "BPKOPLPW123")
+	assert.NoError(t, err)
+	assert.Equal(t, &sql.NullString{String: "BPKOPLPWXXX", Valid: true}, link)
+
+	// Przywrócona centrala sama dołącza oddziały (trigger), nie zmieniamy jej powiązania.
+	link = nil
+	_, err = svc.RestoreSwiftCode(context.Background(), "BPKOPLPWXXX")
+	assert.NoError(t, err)
+	assert.Nil(t, link)
+}
+
+func TestToSwiftCodeResponseBR_Deleted(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	resp := toSwiftCodeResponseBR(repository.SwiftCode{
+		SwiftCode: "BPKOPLPW123",
+		DeletedAt: sql.NullTime{Time: deletedAt, Valid: true},
+		DeletedBy: sql.NullString{String: "jan", Valid: true},
+	})
+	assert.Equal(t, &deletedAt, resp.DeletedAt)
+	assert.Equal(t, "jan", resp.DeletedBy)
+}
+
+func TestPurgeDeletedSwiftCodes(t *testing.T) {
+	var cutoff time.Time
+	mockRepo := &mockSwiftRepo{
+		PurgeDeletedFunc: func(before time.Time) (int, error) {
+			cutoff = before
+			return 4, nil
+		},
+	}
+
+	svc := NewSwiftService(mockRepo)
+	purged, err := svc.PurgeDeletedSwiftCodes(context.Background(), 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, purged)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), cutoff, time.Minute)
+
+	_, err = svc.PurgeDeletedSwiftCodes(context.Background(), -time.Hour)
+	assert.ErrorIs(t, err, ErrValidation)
+}
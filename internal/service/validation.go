@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Is pozwala sprawdzać błędy walidacji przez errors.Is(err, ErrValidation).
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateBIC sprawdza strukturę kodu BIC zgodnie z ISO 9362:
+// 4 litery kodu instytucji, 2 litery kodu kraju, 2 znaki lokalizacji
+// i opcjonalnie 3 znaki oddziału (BIC8 albo BIC11).
+// Kod kraju musi zgadzać się z countryISO2, o ile ten został podany.
+func ValidateBIC(code, countryISO2 string) []FieldError {
+	verr := &ValidationError{}
+
+	if len(code) != 8 && len(code) != 11 {
+		verr.add("swiftCode", "must be 8 (BIC8) or 11 (BIC11) characters long, got %d", len(code))
+		return verr.Fields
+	}
+
+	if !isAlpha(code[0:4]) {
+		verr.add("swiftCode", "institution code %q must consist of 4 letters", code[0:4])
+	}
+	if !isAlpha(code[4:6]) {
+		verr.add("swiftCode", "country code %q must consist of 2 letters", code[4:6])
+	} else if countryISO2 != "" && code[4:6] != countryISO2 {
+		verr.add("swiftCode", "country code %q does not match countryISO2 %q", code[4:6], countryISO2)
+	}
+	if !isAlphanumeric(code[6:8]) {
+		verr.add("swiftCode", "location code %q must consist of 2 letters or digits", code[6:8])
+	}
+	if len(code) == 11 {
+		branch := code[8:11]
+		if !isAlphanumeric(branch) {
+			verr.add("swiftCode", "branch code %q must consist of 3 letters or digits", branch)
+		} else if branch[0] == 'X' && branch != "XXX" {
+			verr.add("swiftCode", "branch code %q may start with 'X' only as \"XXX\"", branch)
+		}
+	}
+
+	return verr.Fields
+}
+
+func validateCreateInput(input CreateSwiftCodeInput) error {
+	verr := &ValidationError{}
+
+	if input.SwiftCode == "" {
+		verr.add("swiftCode", "is required")
+	}
+	if len(input.CountryISO2) != 2 || !isAlpha(input.CountryISO2) {
+		verr.add("countryISO2", "must consist of 2 letters")
+	}
+	if strings.TrimSpace(input.BankName) == "" {
+		verr.add("bankName", "is required")
+	}
+	if strings.TrimSpace(input.CountryName) == "" {
+		verr.add("countryName", "is required")
+	}
+	if len(input.InstitutionType) > 10 {
+		verr.add("institutionType", "must be at most 10 characters long")
+	}
+	if len(input.ZipCode) > 20 {
+		verr.add("zipCode", "must be at most 20 characters long")
+	}
+	if input.SwiftCode != "" {
+		bicErrs := ValidateBIC(input.SwiftCode, input.CountryISO2)
+		verr.Fields = append(verr.Fields, bicErrs...)
+		if len(bicErrs) == 0 {
+			validateHeadquarterFields(input, verr)
+		}
+	}
+
+	return verr.errOrNil()
+}
+
+// Relacja centrala/oddział wynika z kodu, więc wartości przysłane przez klienta
+// mogą ją jedynie potwierdzić.
+func validateHeadquarterFields(input CreateSwiftCodeInput, verr *ValidationError) {
+	isHeadquarter := isHeadquarterCode(input.SwiftCode)
+
+	if input.IsHeadquarter != nil && *input.IsHeadquarter != isHeadquarter {
+		if isHeadquarter {
+			verr.add("isHeadquarter", "must be true for a code ending with \"XXX\"")
+		} else {
+			verr.add("isHeadquarter", "must be false for a branch code not ending with \"XXX\"")
+		}
+	}
+
+	if input.HeadquarterSwiftCode == nil || strings.TrimSpace(*input.HeadquarterSwiftCode) == "" {
+		return
+	}
+	if isHeadquarter {
+		verr.add("headquarterSwiftCode", "must be empty for a headquarter code")
+		return
+	}
+	expected := headquarterCodeFor(input.SwiftCode)
+	if NormalizeBIC(*input.HeadquarterSwiftCode) != expected {
+		verr.add("headquarterSwiftCode", "must be %q (first 8 characters of swiftCode followed by \"XXX\")", expected)
+	}
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS swift.idx_swift_codes_country_bank_name;
+DROP INDEX IF EXISTS swift.idx_swift_codes_country_swift_code;
@@ -0,0 +1,5 @@
+CREATE INDEX idx_swift_codes_country_swift_code
+    ON swift.swift_codes (country_iso2, swift_code);
+
+CREATE INDEX idx_swift_codes_country_bank_name
+    ON swift.swift_codes (country_iso2, bank_name, swift_code);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS swift.idx_swift_codes_address_trgm;
+DROP INDEX IF EXISTS swift.idx_swift_codes_bank_name_trgm;
+DROP INDEX IF EXISTS swift.idx_swift_codes_search_document;
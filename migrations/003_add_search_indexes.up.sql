@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_swift_codes_search_document
+    ON swift.swift_codes USING GIN (to_tsvector('simple', bank_name || ' ' || address));
+
+CREATE INDEX idx_swift_codes_bank_name_trgm
+    ON swift.swift_codes USING GIN (bank_name gin_trgm_ops);
+
+CREATE INDEX idx_swift_codes_address_trgm
+    ON swift.swift_codes USING GIN (address gin_trgm_ops);
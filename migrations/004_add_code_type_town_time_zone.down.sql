@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS swift.idx_swift_codes_search_document;
+
+CREATE INDEX idx_swift_codes_search_document
+    ON swift.swift_codes USING GIN (to_tsvector('simple', bank_name || ' ' || address));
+
+-- Przywracamy dawny format adresu "<ADDRESS>, <TOWN NAME>".
+UPDATE swift.swift_codes
+SET address = address || ', ' || town_name
+WHERE town_name <> '';
+
+ALTER TABLE swift.swift_codes
+    DROP COLUMN time_zone,
+    DROP COLUMN town_name,
+    DROP COLUMN code_type;
@@ -0,0 +1,17 @@
+ALTER TABLE swift.swift_codes
+    ADD COLUMN code_type VARCHAR(10) NOT NULL DEFAULT 'BIC11',
+    ADD COLUMN town_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Dotychczasowy import zapisywał adres jako "<ADDRESS>, <TOWN NAME>" (także przy
+-- pustym adresie albo mieście), więc miasto to ostatni człon po przecinku - przenosimy
+-- go do town_name, a przecinki wewnątrz samego adresu zostają.
+UPDATE swift.swift_codes
+SET town_name = btrim(substring(address from ',([^,]*)$')),
+    address = btrim(regexp_replace(address, '\s*,[^,]*$', ''))
+WHERE address LIKE '%,%';
+
+DROP INDEX IF EXISTS swift.idx_swift_codes_search_document;
+
+CREATE INDEX idx_swift_codes_search_document
+    ON swift.swift_codes USING GIN (to_tsvector('simple', bank_name || ' ' || address || ' ' || town_name));
@@ -0,0 +1,4 @@
+ALTER TABLE swift.swift_codes
+    DROP COLUMN institution_type,
+    DROP COLUMN zip_code,
+    DROP COLUMN branch_information;
@@ -0,0 +1,5 @@
+-- Dodatkowe kolumny z plików SWIFTRef BIC Directory / BICPlus.
+ALTER TABLE swift.swift_codes
+    ADD COLUMN branch_information VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN zip_code VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN institution_type VARCHAR(10) NOT NULL DEFAULT '';
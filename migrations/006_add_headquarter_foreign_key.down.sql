@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS trg_swift_codes_attach_branches ON swift.swift_codes;
+DROP TRIGGER IF EXISTS trg_swift_codes_link_headquarter ON swift.swift_codes;
+DROP FUNCTION IF EXISTS swift.attach_branches();
+DROP FUNCTION IF EXISTS swift.link_headquarter();
+
+DROP INDEX IF EXISTS swift.idx_swift_codes_detached_branches;
+DROP INDEX IF EXISTS swift.idx_swift_codes_headquarter_swift_code;
+
+ALTER TABLE swift.swift_codes
+    DROP CONSTRAINT IF EXISTS fk_swift_codes_headquarter;
+
+-- Przywracamy wyliczany kod centrali także oddziałom bez centrali w bazie.
+UPDATE swift.swift_codes
+SET headquarter_swift_code = left(swift_code, 8) || 'XXX'
+WHERE headquarter_swift_code IS NULL
+  AND NOT is_headquarter;
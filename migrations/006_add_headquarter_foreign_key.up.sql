@@ -0,0 +1,51 @@
+-- Oddział wskazuje centralę tylko wtedy, gdy ta istnieje w bazie. Oddziały,
+-- których centrali nie ma (np. w swift_data.xlsx), mają headquarter_swift_code = NULL
+-- i są dołączane automatycznie, gdy centrala zostanie dodana.
+UPDATE swift.swift_codes b
+SET headquarter_swift_code = NULL
+WHERE headquarter_swift_code IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM swift.swift_codes h WHERE h.swift_code = b.headquarter_swift_code);
+
+-- Usunięcie centrali bez jawnej polityki w API (import w trybie sync, flaga D)
+-- odłącza jej oddziały zamiast zostawiać je z nieistniejącym kodem.
+ALTER TABLE swift.swift_codes
+    ADD CONSTRAINT fk_swift_codes_headquarter
+        FOREIGN KEY (headquarter_swift_code) REFERENCES swift.swift_codes (swift_code)
+        ON DELETE SET NULL;
+
+CREATE INDEX idx_swift_codes_headquarter_swift_code
+    ON swift.swift_codes (headquarter_swift_code);
+
+CREATE INDEX idx_swift_codes_detached_branches
+    ON swift.swift_codes (left(swift_code, 8))
+    WHERE headquarter_swift_code IS NULL AND NOT is_headquarter;
+
+CREATE FUNCTION swift.link_headquarter() RETURNS trigger AS $$
+BEGIN
+    IF NEW.headquarter_swift_code IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM swift.swift_codes WHERE swift_code = NEW.headquarter_swift_code
+    ) THEN
+        NEW.headquarter_swift_code := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_swift_codes_link_headquarter
+    BEFORE INSERT OR UPDATE OF headquarter_swift_code ON swift.swift_codes
+    FOR EACH ROW EXECUTE FUNCTION swift.link_headquarter();
+
+CREATE FUNCTION swift.attach_branches() RETURNS trigger AS $$
+BEGIN
+    UPDATE swift.swift_codes
+    SET headquarter_swift_code = NEW.swift_code
+    WHERE headquarter_swift_code IS NULL
+      AND NOT is_headquarter
+      AND left(swift_code, 8) = left(NEW.swift_code, 8);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_swift_codes_attach_branches
+    AFTER INSERT ON swift.swift_codes
+    FOR EACH ROW WHEN (NEW.is_headquarter) EXECUTE FUNCTION swift.attach_branches();
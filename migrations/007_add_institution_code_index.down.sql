@@ -0,0 +1 @@
+DROP INDEX IF EXISTS swift.idx_swift_codes_institution_code;
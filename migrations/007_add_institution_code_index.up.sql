@@ -0,0 +1,3 @@
+-- Kod instytucji to pierwsze 4 znaki BIC; indeks obsługuje GET /v1/banks/{institutionCode}.
+CREATE INDEX idx_swift_codes_institution_code
+    ON swift.swift_codes (left(swift_code, 4), swift_code);
@@ -0,0 +1,259 @@
+-- Słownik krajów ISO 3166-1 (alpha-2, alpha-3, nazwa w stylu pliku SWIFT).
+-- XK (Kosowo) nie ma oficjalnego kodu ISO, ale SWIFT go używa.
+CREATE TABLE swift.countries (
+    iso2 CHAR(2) PRIMARY KEY,
+    iso3 CHAR(3) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL
+);
+
+INSERT INTO swift.countries (iso2, iso3, name) VALUES
+    ('AD', 'AND', 'ANDORRA'),
+    ('AE', 'ARE', 'UNITED ARAB EMIRATES'),
+    ('AF', 'AFG', 'AFGHANISTAN'),
+    ('AG', 'ATG', 'ANTIGUA AND BARBUDA'),
+    ('AI', 'AIA', 'ANGUILLA'),
+    ('AL', 'ALB', 'ALBANIA'),
+    ('AM', 'ARM', 'ARMENIA'),
+    ('AO', 'AGO', 'ANGOLA'),
+    ('AQ', 'ATA', 'ANTARCTICA'),
+    ('AR', 'ARG', 'ARGENTINA'),
+    ('AS', 'ASM', 'AMERICAN SAMOA'),
+    ('AT', 'AUT', 'AUSTRIA'),
+    ('AU', 'AUS', 'AUSTRALIA'),
+    ('AW', 'ABW', 'ARUBA'),
+    ('AX', 'ALA', 'ALAND ISLANDS'),
+    ('AZ', 'AZE', 'AZERBAIJAN'),
+    ('BA', 'BIH', 'BOSNIA AND HERZEGOVINA'),
+    ('BB', 'BRB', 'BARBADOS'),
+    ('BD', 'BGD', 'BANGLADESH'),
+    ('BE', 'BEL', 'BELGIUM'),
+    ('BF', 'BFA', 'BURKINA FASO'),
+    ('BG', 'BGR', 'BULGARIA'),
+    ('BH', 'BHR', 'BAHRAIN'),
+    ('BI', 'BDI', 'BURUNDI'),
+    ('BJ', 'BEN', 'BENIN'),
+    ('BL', 'BLM', 'SAINT BARTHELEMY'),
+    ('BM', 'BMU', 'BERMUDA'),
+    ('BN', 'BRN', 'BRUNEI DARUSSALAM'),
+    ('BO', 'BOL', 'BOLIVIA, PLURINATIONAL STATE OF'),
+    ('BQ', 'BES', 'BONAIRE, SINT EUSTATIUS AND SABA'),
+    ('BR', 'BRA', 'BRAZIL'),
+    ('BS', 'BHS', 'BAHAMAS'),
+    ('BT', 'BTN', 'BHUTAN'),
+    ('BV', 'BVT', 'BOUVET ISLAND'),
+    ('BW', 'BWA', 'BOTSWANA'),
+    ('BY', 'BLR', 'BELARUS'),
+    ('BZ', 'BLZ', 'BELIZE'),
+    ('CA', 'CAN', 'CANADA'),
+    ('CC', 'CCK', 'COCOS (KEELING) ISLANDS'),
+    ('CD', 'COD', 'CONGO, THE DEMOCRATIC REPUBLIC OF THE'),
+    ('CF', 'CAF', 'CENTRAL AFRICAN REPUBLIC'),
+    ('CG', 'COG', 'CONGO'),
+    ('CH', 'CHE', 'SWITZERLAND'),
+    ('CI', 'CIV', 'COTE D''IVOIRE'),
+    ('CK', 'COK', 'COOK ISLANDS'),
+    ('CL', 'CHL', 'CHILE'),
+    ('CM', 'CMR', 'CAMEROON'),
+    ('CN', 'CHN', 'CHINA'),
+    ('CO', 'COL', 'COLOMBIA'),
+    ('CR', 'CRI', 'COSTA RICA'),
+    ('CU', 'CUB', 'CUBA'),
+    ('CV', 'CPV', 'CABO VERDE'),
+    ('CW', 'CUW', 'CURACAO'),
+    ('CX', 'CXR', 'CHRISTMAS ISLAND'),
+    ('CY', 'CYP', 'CYPRUS'),
+    ('CZ', 'CZE', 'CZECHIA'),
+    ('DE', 'DEU', 'GERMANY'),
+    ('DJ', 'DJI', 'DJIBOUTI'),
+    ('DK', 'DNK', 'DENMARK'),
+    ('DM', 'DMA', 'DOMINICA'),
+    ('DO', 'DOM', 'DOMINICAN REPUBLIC'),
+    ('DZ', 'DZA', 'ALGERIA'),
+    ('EC', 'ECU', 'ECUADOR'),
+    ('EE', 'EST', 'ESTONIA'),
+    ('EG', 'EGY', 'EGYPT'),
+    ('EH', 'ESH', 'WESTERN SAHARA'),
+    ('ER', 'ERI', 'ERITREA'),
+    ('ES', 'ESP', 'SPAIN'),
+    ('ET', 'ETH', 'ETHIOPIA'),
+    ('FI', 'FIN', 'FINLAND'),
+    ('FJ', 'FJI', 'FIJI'),
+    ('FK', 'FLK', 'FALKLAND ISLANDS (MALVINAS)'),
+    ('FM', 'FSM', 'MICRONESIA, FEDERATED STATES OF'),
+    ('FO', 'FRO', 'FAROE ISLANDS'),
+    ('FR', 'FRA', 'FRANCE'),
+    ('GA', 'GAB', 'GABON'),
+    ('GB', 'GBR', 'UNITED KINGDOM'),
+    ('GD', 'GRD', 'GRENADA'),
+    ('GE', 'GEO', 'GEORGIA'),
+    ('GF', 'GUF', 'FRENCH GUIANA'),
+    ('GG', 'GGY', 'GUERNSEY'),
+    ('GH', 'GHA', 'GHANA'),
+    ('GI', 'GIB', 'GIBRALTAR'),
+    ('GL', 'GRL', 'GREENLAND'),
+    ('GM', 'GMB', 'GAMBIA'),
+    ('GN', 'GIN', 'GUINEA'),
+    ('GP', 'GLP', 'GUADELOUPE'),
+    ('GQ', 'GNQ', 'EQUATORIAL GUINEA'),
+    ('GR', 'GRC', 'GREECE'),
+    ('GS', 'SGS', 'SOUTH GEORGIA AND THE SOUTH SANDWICH ISLANDS'),
+    ('GT', 'GTM', 'GUATEMALA'),
+    ('GU', 'GUM', 'GUAM'),
+    ('GW', 'GNB', 'GUINEA-BISSAU'),
+    ('GY', 'GUY', 'GUYANA'),
+    ('HK', 'HKG', 'HONG KONG'),
+    ('HM', 'HMD', 'HEARD ISLAND AND MCDONALD ISLANDS'),
+    ('HN', 'HND', 'HONDURAS'),
+    ('HR', 'HRV', 'CROATIA'),
+    ('HT', 'HTI', 'HAITI'),
+    ('HU', 'HUN', 'HUNGARY'),
+    ('ID', 'IDN', 'INDONESIA'),
+    ('IE', 'IRL', 'IRELAND'),
+    ('IL', 'ISR', 'ISRAEL'),
+    ('IM', 'IMN', 'ISLE OF MAN'),
+    ('IN', 'IND', 'INDIA'),
+    ('IO', 'IOT', 'BRITISH INDIAN OCEAN TERRITORY'),
+    ('IQ', 'IRQ', 'IRAQ'),
+    ('IR', 'IRN', 'IRAN, ISLAMIC REPUBLIC OF'),
+    ('IS', 'ISL', 'ICELAND'),
+    ('IT', 'ITA', 'ITALY'),
+    ('JE', 'JEY', 'JERSEY'),
+    ('JM', 'JAM', 'JAMAICA'),
+    ('JO', 'JOR', 'JORDAN'),
+    ('JP', 'JPN', 'JAPAN'),
+    ('KE', 'KEN', 'KENYA'),
+    ('KG', 'KGZ', 'KYRGYZSTAN'),
+    ('KH', 'KHM', 'CAMBODIA'),
+    ('KI', 'KIR', 'KIRIBATI'),
+    ('KM', 'COM', 'COMOROS'),
+    ('KN', 'KNA', 'SAINT KITTS AND NEVIS'),
+    ('KP', 'PRK', 'KOREA, DEMOCRATIC PEOPLE''S REPUBLIC OF'),
+    ('KR', 'KOR', 'KOREA, REPUBLIC OF'),
+    ('KW', 'KWT', 'KUWAIT'),
+    ('KY', 'CYM', 'CAYMAN ISLANDS'),
+    ('KZ', 'KAZ', 'KAZAKHSTAN'),
+    ('LA', 'LAO', 'LAO PEOPLE''S DEMOCRATIC REPUBLIC'),
+    ('LB', 'LBN', 'LEBANON'),
+    ('LC', 'LCA', 'SAINT LUCIA'),
+    ('LI', 'LIE', 'LIECHTENSTEIN'),
+    ('LK', 'LKA', 'SRI LANKA'),
+    ('LR', 'LBR', 'LIBERIA'),
+    ('LS', 'LSO', 'LESOTHO'),
+    ('LT', 'LTU', 'LITHUANIA'),
+    ('LU', 'LUX', 'LUXEMBOURG'),
+    ('LV', 'LVA', 'LATVIA'),
+    ('LY', 'LBY', 'LIBYA'),
+    ('MA', 'MAR', 'MOROCCO'),
+    ('MC', 'MCO', 'MONACO'),
+    ('MD', 'MDA', 'MOLDOVA, REPUBLIC OF'),
+    ('ME', 'MNE', 'MONTENEGRO'),
+    ('MF', 'MAF', 'SAINT MARTIN (FRENCH PART)'),
+    ('MG', 'MDG', 'MADAGASCAR'),
+    ('MH', 'MHL', 'MARSHALL ISLANDS'),
+    ('MK', 'MKD', 'NORTH MACEDONIA'),
+    ('ML', 'MLI', 'MALI'),
+    ('MM', 'MMR', 'MYANMAR'),
+    ('MN', 'MNG', 'MONGOLIA'),
+    ('MO', 'MAC', 'MACAO'),
+    ('MP', 'MNP', 'NORTHERN MARIANA ISLANDS'),
+    ('MQ', 'MTQ', 'MARTINIQUE'),
+    ('MR', 'MRT', 'MAURITANIA'),
+    ('MS', 'MSR', 'MONTSERRAT'),
+    ('MT', 'MLT', 'MALTA'),
+    ('MU', 'MUS', 'MAURITIUS'),
+    ('MV', 'MDV', 'MALDIVES'),
+    ('MW', 'MWI', 'MALAWI'),
+    ('MX', 'MEX', 'MEXICO'),
+    ('MY', 'MYS', 'MALAYSIA'),
+    ('MZ', 'MOZ', 'MOZAMBIQUE'),
+    ('NA', 'NAM', 'NAMIBIA'),
+    ('NC', 'NCL', 'NEW CALEDONIA'),
+    ('NE', 'NER', 'NIGER'),
+    ('NF', 'NFK', 'NORFOLK ISLAND'),
+    ('NG', 'NGA', 'NIGERIA'),
+    ('NI', 'NIC', 'NICARAGUA'),
+    ('NL', 'NLD', 'NETHERLANDS'),
+    ('NO', 'NOR', 'NORWAY'),
+    ('NP', 'NPL', 'NEPAL'),
+    ('NR', 'NRU', 'NAURU'),
+    ('NU', 'NIU', 'NIUE'),
+    ('NZ', 'NZL', 'NEW ZEALAND'),
+    ('OM', 'OMN', 'OMAN'),
+    ('PA', 'PAN', 'PANAMA'),
+    ('PE', 'PER', 'PERU'),
+    ('PF', 'PYF', 'FRENCH POLYNESIA'),
+    ('PG', 'PNG', 'PAPUA NEW GUINEA'),
+    ('PH', 'PHL', 'PHILIPPINES'),
+    ('PK', 'PAK', 'PAKISTAN'),
+    ('PL', 'POL', 'POLAND'),
+    ('PM', 'SPM', 'SAINT PIERRE AND MIQUELON'),
+    ('PN', 'PCN', 'PITCAIRN'),
+    ('PR', 'PRI', 'PUERTO RICO'),
+    ('PS', 'PSE', 'PALESTINE, STATE OF'),
+    ('PT', 'PRT', 'PORTUGAL'),
+    ('PW', 'PLW', 'PALAU'),
+    ('PY', 'PRY', 'PARAGUAY'),
+    ('QA', 'QAT', 'QATAR'),
+    ('RE', 'REU', 'REUNION'),
+    ('RO', 'ROU', 'ROMANIA'),
+    ('RS', 'SRB', 'SERBIA'),
+    ('RU', 'RUS', 'RUSSIAN FEDERATION'),
+    ('RW', 'RWA', 'RWANDA'),
+    ('SA', 'SAU', 'SAUDI ARABIA'),
+    ('SB', 'SLB', 'SOLOMON ISLANDS'),
+    ('SC', 'SYC', 'SEYCHELLES'),
+    ('SD', 'SDN', 'SUDAN'),
+    ('SE', 'SWE', 'SWEDEN'),
+    ('SG', 'SGP', 'SINGAPORE'),
+    ('SH', 'SHN', 'SAINT HELENA, ASCENSION AND TRISTAN DA CUNHA'),
+    ('SI', 'SVN', 'SLOVENIA'),
+    ('SJ', 'SJM', 'SVALBARD AND JAN MAYEN'),
+    ('SK', 'SVK', 'SLOVAKIA'),
+    ('SL', 'SLE', 'SIERRA LEONE'),
+    ('SM', 'SMR', 'SAN MARINO'),
+    ('SN', 'SEN', 'SENEGAL'),
+    ('SO', 'SOM', 'SOMALIA'),
+    ('SR', 'SUR', 'SURINAME'),
+    ('SS', 'SSD', 'SOUTH SUDAN'),
+    ('ST', 'STP', 'SAO TOME AND PRINCIPE'),
+    ('SV', 'SLV', 'EL SALVADOR'),
+    ('SX', 'SXM', 'SINT MAARTEN (DUTCH PART)'),
+    ('SY', 'SYR', 'SYRIAN ARAB REPUBLIC'),
+    ('SZ', 'SWZ', 'ESWATINI'),
+    ('TC', 'TCA', 'TURKS AND CAICOS ISLANDS'),
+    ('TD', 'TCD', 'CHAD'),
+    ('TF', 'ATF', 'FRENCH SOUTHERN TERRITORIES'),
+    ('TG', 'TGO', 'TOGO'),
+    ('TH', 'THA', 'THAILAND'),
+    ('TJ', 'TJK', 'TAJIKISTAN'),
+    ('TK', 'TKL', 'TOKELAU'),
+    ('TL', 'TLS', 'TIMOR-LESTE'),
+    ('TM', 'TKM', 'TURKMENISTAN'),
+    ('TN', 'TUN', 'TUNISIA'),
+    ('TO', 'TON', 'TONGA'),
+    ('TR', 'TUR', 'TURKIYE'),
+    ('TT', 'TTO', 'TRINIDAD AND TOBAGO'),
+    ('TV', 'TUV', 'TUVALU'),
+    ('TW', 'TWN', 'TAIWAN, PROVINCE OF CHINA'),
+    ('TZ', 'TZA', 'TANZANIA, UNITED REPUBLIC OF'),
+    ('UA', 'UKR', 'UKRAINE'),
+    ('UG', 'UGA', 'UGANDA'),
+    ('UM', 'UMI', 'UNITED STATES MINOR OUTLYING ISLANDS'),
+    ('US', 'USA', 'UNITED STATES'),
+    ('UY', 'URY', 'URUGUAY'),
+    ('UZ', 'UZB', 'UZBEKISTAN'),
+    ('VA', 'VAT', 'HOLY SEE (VATICAN CITY STATE)'),
+    ('VC', 'VCT', 'SAINT VINCENT AND THE GRENADINES'),
+    ('VE', 'VEN', 'VENEZUELA, BOLIVARIAN REPUBLIC OF'),
+    ('VG', 'VGB', 'VIRGIN ISLANDS, BRITISH'),
+    ('VI', 'VIR', 'VIRGIN ISLANDS, U.S.'),
+    ('VN', 'VNM', 'VIET NAM'),
+    ('VU', 'VUT', 'VANUATU'),
+    ('WF', 'WLF', 'WALLIS AND FUTUNA'),
+    ('WS', 'WSM', 'SAMOA'),
+    ('YE', 'YEM', 'YEMEN'),
+    ('YT', 'MYT', 'MAYOTTE'),
+    ('ZA', 'ZAF', 'SOUTH AFRICA'),
+    ('ZM', 'ZMB', 'ZAMBIA'),
+    ('ZW', 'ZWE', 'ZIMBABWE'),
+    ('XK', 'XKX', 'KOSOVO');
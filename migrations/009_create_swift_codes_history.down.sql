@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_swift_codes_record_history ON swift.swift_codes;
+DROP FUNCTION IF EXISTS swift.record_history();
+DROP TABLE IF EXISTS swift.swift_codes_history;
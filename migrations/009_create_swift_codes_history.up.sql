@@ -0,0 +1,45 @@
+-- Historia zmian każdego rekordu. Kto i skąd zmienia dane, aplikacja przekazuje
+-- w transakcji przez set_config('swift.actor' / 'swift.source'); zmiany wykonane
+-- bezpośrednio w SQL trafiają do historii jako użytkownik bazy ze źródłem 'sql'.
+CREATE TABLE swift.swift_codes_history (
+    id BIGSERIAL PRIMARY KEY,
+    swift_code VARCHAR(11) NOT NULL,
+    operation VARCHAR(6) NOT NULL CHECK (operation IN ('INSERT', 'UPDATE', 'DELETE')),
+    old_values JSONB,
+    new_values JSONB,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    actor TEXT NOT NULL,
+    source TEXT NOT NULL
+);
+
+-- varchar_pattern_ops obsługuje zarówno równość, jak i prefiks BIC8 (oddziały na dany dzień).
+CREATE INDEX idx_swift_codes_history_swift_code
+    ON swift.swift_codes_history (swift_code varchar_pattern_ops, changed_at DESC, id DESC);
+
+CREATE FUNCTION swift.record_history() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD IS NOT DISTINCT FROM NEW THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO swift.swift_codes_history (swift_code, operation, old_values, new_values, actor, source)
+    VALUES (
+        CASE WHEN TG_OP = 'DELETE' THEN OLD.swift_code ELSE NEW.swift_code END,
+        TG_OP,
+        CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE to_jsonb(OLD) END,
+        CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE to_jsonb(NEW) END,
+        COALESCE(NULLIF(current_setting('swift.actor', true), ''), session_user),
+        COALESCE(NULLIF(current_setting('swift.source', true), ''), 'sql')
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_swift_codes_record_history
+    AFTER INSERT OR UPDATE OR DELETE ON swift.swift_codes
+    FOR EACH ROW EXECUTE FUNCTION swift.record_history();
+
+-- Stan sprzed migracji zapisujemy jako punkt startowy historii.
+INSERT INTO swift.swift_codes_history (swift_code, operation, new_values, actor, source)
+SELECT swift_code, 'INSERT', to_jsonb(s), session_user, 'migration'
+FROM swift.swift_codes s;
@@ -0,0 +1,21 @@
+DROP TRIGGER IF EXISTS trg_swift_codes_attach_branches_on_restore ON swift.swift_codes;
+
+CREATE OR REPLACE FUNCTION swift.link_headquarter() RETURNS trigger AS $$
+BEGIN
+    IF NEW.headquarter_swift_code IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM swift.swift_codes WHERE swift_code = NEW.headquarter_swift_code
+    ) THEN
+        NEW.headquarter_swift_code := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Rekordy oznaczone jako usunięte nie mają odpowiednika bez tych kolumn.
+DELETE FROM swift.swift_codes WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS swift.idx_swift_codes_deleted_at;
+
+ALTER TABLE swift.swift_codes
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
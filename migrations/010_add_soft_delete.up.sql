@@ -0,0 +1,28 @@
+-- Usunięcie przez API tylko oznacza rekord; fizycznie usuwa go dopiero
+-- swiftctl purge po okresie retencji.
+ALTER TABLE swift.swift_codes
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by TEXT;
+
+CREATE INDEX idx_swift_codes_deleted_at
+    ON swift.swift_codes (deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- Usunięta centrala nie istnieje dla nowych oddziałów - tak jak brakująca.
+CREATE OR REPLACE FUNCTION swift.link_headquarter() RETURNS trigger AS $$
+BEGIN
+    IF NEW.headquarter_swift_code IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM swift.swift_codes
+        WHERE swift_code = NEW.headquarter_swift_code AND deleted_at IS NULL
+    ) THEN
+        NEW.headquarter_swift_code := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Przywrócona centrala (restore albo ponowny import) dołącza odłączone oddziały.
+CREATE TRIGGER trg_swift_codes_attach_branches_on_restore
+    AFTER UPDATE OF deleted_at ON swift.swift_codes
+    FOR EACH ROW WHEN (NEW.is_headquarter AND OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL)
+    EXECUTE FUNCTION swift.attach_branches();